@@ -30,12 +30,366 @@ type HelloWorldSpec struct {
 
 	// Message is a string field that will be printed to the logs by the helloworld_controller
 	Message string `json:"message,omitempty"`
+
+	// Image is the nginx image serving the page. Tags are resolved to digests
+	// by the controller and the Deployment is pinned to the resolved digest.
+	// Defaults to nginxinc/nginx-unprivileged:latest.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Rollback configures what happens when a rollout of new content fails
+	// +optional
+	Rollback *RollbackSpec `json:"rollback,omitempty"`
+
+	// Revision pins the served page to a named content revision from the
+	// revision history instead of rendering Message
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// RevisionHistoryLimit is the number of old content revisions to keep.
+	// The active, pinned and last known-good revisions are always kept.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=10
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// Replicas is the number of nginx replicas serving the stable revision
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Strategy controls how new page content is rolled out
+	// +optional
+	Strategy *StrategySpec `json:"strategy,omitempty"`
+
+	// Schedule serves other messages during scheduled windows. The first
+	// entry whose window contains the current time wins; outside of every
+	// window Message is served.
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	Schedule []ScheduleEntry `json:"schedule,omitempty"`
+
+	// Exposure is how the page is exposed. Defaults to the default exposure
+	// of the HelloWorldPolicies applying to the namespace, or Route.
+	// +optional
+	Exposure ExposureType `json:"exposure,omitempty"`
+}
+
+// ExposureType is the way the page of a HelloWorld is exposed.
+// +kubebuilder:validation:Enum=Service;Route
+type ExposureType string
+
+const (
+	// ServiceExposureType only serves the page inside the cluster, through a Service
+	ServiceExposureType ExposureType = "Service"
+	// RouteExposureType also serves the page outside of the cluster through an
+	// OpenShift Route, when the cluster serves Routes
+	RouteExposureType ExposureType = "Route"
+)
+
+// ScheduleEntry is a recurring or one-off window during which a message is served.
+// +kubebuilder:validation:XValidation:rule="has(self.cron) != has(self.start)",message="exactly one of cron or start must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.cron) || has(self.duration)",message="duration is required with cron"
+// +kubebuilder:validation:XValidation:rule="!has(self.start) || has(self.end)",message="end is required with start"
+type ScheduleEntry struct {
+	// Cron opens a window every time the standard five-field cron expression
+	// fires, evaluated in UTC unless prefixed with CRON_TZ=<zone>
+	// +optional
+	Cron string `json:"cron,omitempty"`
+
+	// Duration is how long a window opened by Cron stays open
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Start opens a one-off window
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End closes the one-off window opened by Start
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Message is served while the window is open
+	Message string `json:"message"`
 }
 
+// StrategyType is the way new page content is rolled out.
+// +kubebuilder:validation:Enum=RollingUpdate;BlueGreen;Canary
+type StrategyType string
+
+const (
+	// RollingUpdateStrategyType replaces the served content in place
+	RollingUpdateStrategyType StrategyType = "RollingUpdate"
+	// BlueGreenStrategyType runs new content next to the stable content without
+	// sending it traffic, and switches all traffic over once promoted
+	BlueGreenStrategyType StrategyType = "BlueGreen"
+	// CanaryStrategyType sends a share of the traffic to new content until it is promoted
+	CanaryStrategyType StrategyType = "Canary"
+)
+
+// StrategySpec configures blue/green and canary rollouts of page content. New
+// content becomes the candidate revision, served by a second nginx Deployment,
+// until it is promoted or the rollout is aborted.
+type StrategySpec struct {
+	// Type is the rollout strategy
+	// +kubebuilder:default=RollingUpdate
+	// +optional
+	Type StrategyType `json:"type,omitempty"`
+
+	// Canary configures the Canary strategy
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+
+	// Promote promotes the candidate revision to stable once it names the
+	// candidate, as reported in status.strategy.candidateRevision
+	// +optional
+	Promote string `json:"promote,omitempty"`
+
+	// Abort stops serving the candidate revision and returns all traffic to
+	// the stable revision for as long as it is set
+	// +optional
+	Abort bool `json:"abort,omitempty"`
+}
+
+// CanaryStrategy configures the Canary strategy.
+type CanaryStrategy struct {
+	// Weight is the percentage of traffic sent to the candidate revision
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// DefaultRevisionHistoryLimit is the number of old content revisions kept when
+// spec.revisionHistoryLimit is not set.
+const DefaultRevisionHistoryLimit int32 = 10
+
+// RollbackSpec configures automatic rollback of failed rollouts.
+type RollbackSpec struct {
+	// OnFailure reverts the ConfigMap and Deployment to the last known-good
+	// revision when the Deployment exceeds its progress deadline
+	// +optional
+	OnFailure bool `json:"onFailure,omitempty"`
+}
+
+// AppliedPolicy is the combination of the HelloWorldPolicies applying to a
+// HelloWorld. Limits combine to the strictest, and the defaults of policies
+// earlier in name order take precedence.
+type AppliedPolicy struct {
+	// Policies names the HelloWorldPolicies applying to the HelloWorld, in name order
+	Policies []string `json:"policies"`
+
+	// AllowedImages lists the registries, or registry/repository prefixes,
+	// allowed by every policy. Any image is allowed if unset, none if empty.
+	// +optional
+	AllowedImages []string `json:"allowedImages"`
+
+	// DefaultImage is the nginx image of HelloWorlds that do not set spec.image
+	// +optional
+	DefaultImage string `json:"defaultImage,omitempty"`
+
+	// MaxReplicas is the most nginx replicas the HelloWorld is served with
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// RequiredLabels lists the label keys the HelloWorld must set
+	// +optional
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+
+	// AllowedExposures lists the exposures allowed by every policy. Any
+	// exposure is allowed if unset, none if empty.
+	// +optional
+	AllowedExposures []ExposureType `json:"allowedExposures"`
+
+	// DefaultExposure is the exposure of HelloWorlds that do not set spec.exposure
+	// +optional
+	DefaultExposure ExposureType `json:"defaultExposure,omitempty"`
+
+	// MaxMessageSize is the largest message, in bytes, the HelloWorld may serve
+	// +optional
+	MaxMessageSize *int32 `json:"maxMessageSize,omitempty"`
+
+	// Quota is the strictest quota of the policies
+	// +optional
+	Quota *NamespaceQuota `json:"quota,omitempty"`
+}
+
+// HelloWorldRevision is the content and image a HelloWorld serves.
+type HelloWorldRevision struct {
+	// Content is the name of the immutable ConfigMap holding the rendered page
+	Content string `json:"content"`
+
+	// Image is the digest-pinned nginx image
+	Image string `json:"image"`
+}
+
+// RolloutStatus reports the progress of the nginx Deployment rollout.
+type RolloutStatus struct {
+	// ObservedGeneration is the Deployment generation observed by the Deployment controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Replicas is the desired number of nginx replicas
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// UpdatedReplicas is the number of replicas running the current revision
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// AvailableReplicas is the number of available replicas
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+}
+
+// StrategyPhase is the phase of a blue/green or canary rollout.
+type StrategyPhase string
+
+const (
+	// StrategyPhaseStable means only the stable revision is served
+	StrategyPhaseStable StrategyPhase = "Stable"
+	// StrategyPhasePreview means a blue/green candidate runs without traffic, waiting for promotion
+	StrategyPhasePreview StrategyPhase = "Preview"
+	// StrategyPhaseCanary means a canary candidate receives its share of the traffic
+	StrategyPhaseCanary StrategyPhase = "Canary"
+	// StrategyPhasePromoting means the candidate was promoted and receives all
+	// traffic while the stable Deployment rolls out to it
+	StrategyPhasePromoting StrategyPhase = "Promoting"
+	// StrategyPhaseAborted means the candidate was aborted and the stable revision receives all traffic
+	StrategyPhaseAborted StrategyPhase = "Aborted"
+)
+
+// StrategyStatus reports the progress of a blue/green or canary rollout.
+type StrategyStatus struct {
+	// Phase is the phase of the rollout
+	// +optional
+	Phase StrategyPhase `json:"phase,omitempty"`
+
+	// StableRevision is the content revision served by the stable Deployment
+	// +optional
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// CandidateRevision is the content revision being rolled out, if any
+	// +optional
+	CandidateRevision string `json:"candidateRevision,omitempty"`
+
+	// CanaryWeight is the percentage of traffic currently sent to the candidate revision
+	// +optional
+	CanaryWeight int32 `json:"canaryWeight,omitempty"`
+}
+
+// ScheduleStatus reports the state of the message schedule.
+type ScheduleStatus struct {
+	// ActiveEntry is the index of the schedule entry being served, unset
+	// when no window is open
+	// +optional
+	ActiveEntry *int32 `json:"activeEntry,omitempty"`
+
+	// NextTransition is when the served message is next expected to change
+	// +optional
+	NextTransition *metav1.Time `json:"nextTransition,omitempty"`
+}
+
+// PlannedChange is a change to a child of the HelloWorld that the controller
+// would make, reported when it runs in dry-run mode.
+type PlannedChange struct {
+	// Action is what would be done to the child
+	// +kubebuilder:validation:Enum=Create;Update;Delete
+	Action string `json:"action"`
+
+	// Kind is the kind of the child
+	Kind string `json:"kind"`
+
+	// Name is the name of the child
+	Name string `json:"name"`
+
+	// Diff is the JSON merge patch an Update would apply
+	// +optional
+	Diff string `json:"diff,omitempty"`
+}
+
+// Condition types reported on HelloWorld.
+const (
+	// ConditionTypeAvailable indicates that nginx has available replicas serving the page
+	ConditionTypeAvailable = "Available"
+	// ConditionTypeProgressing indicates that a rollout of the nginx Deployment is in progress
+	ConditionTypeProgressing = "Progressing"
+	// ConditionTypeRolledBack indicates that the current generation failed to roll out
+	// and the last known-good revision is being served instead
+	ConditionTypeRolledBack = "RolledBack"
+	// ConditionTypeChildConflict indicates that a child of the HelloWorld already
+	// exists but is not controlled by it, and is left untouched
+	ConditionTypeChildConflict = "ChildConflict"
+	// ConditionTypeContentVerified indicates that the page served by the stable
+	// Service matches a content revision the HelloWorld serves. Only reported
+	// when the controller probes the served content.
+	ConditionTypeContentVerified = "ContentVerified"
+	// ConditionTypePolicyCompliant indicates whether the HelloWorld complies
+	// with the HelloWorldPolicies applying to its namespace. HelloWorlds
+	// created before a policy may break it, and are served within its limits.
+	ConditionTypePolicyCompliant = "PolicyCompliant"
+	// ConditionTypeQuotaExceeded indicates that the HelloWorld is over the
+	// quota of its namespace, which HelloWorlds created before the quota may
+	// be. It is still served.
+	ConditionTypeQuotaExceeded = "QuotaExceeded"
+)
+
 // HelloWorldStatus defines the observed state of HelloWorld.
 type HelloWorldStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// Image is the image reference the digest was resolved from
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ImageDigest is the digest the Deployment is pinned to
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+
+	// ActiveRevision is the name of the content revision served by the stable Deployment
+	// +optional
+	ActiveRevision string `json:"activeRevision,omitempty"`
+
+	// ObservedGeneration is the HelloWorld generation the status was computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Rollout reports the progress of the nginx Deployment rollout
+	// +optional
+	Rollout RolloutStatus `json:"rollout,omitempty"`
+
+	// Schedule reports which scheduled message is served and when that changes
+	// +optional
+	Schedule *ScheduleStatus `json:"schedule,omitempty"`
+
+	// Strategy reports the progress of blue/green and canary rollouts
+	// +optional
+	Strategy *StrategyStatus `json:"strategy,omitempty"`
+
+	// LastKnownGood is the last revision that rolled out successfully, used
+	// as the rollback target
+	// +optional
+	LastKnownGood *HelloWorldRevision `json:"lastKnownGood,omitempty"`
+
+	// Policy is the combination of the HelloWorldPolicies the HelloWorld was
+	// last served with, unset when none applies
+	// +optional
+	Policy *AppliedPolicy `json:"policy,omitempty"`
+
+	// PlannedChanges lists what the controller would change in the children
+	// of the HelloWorld. It is only reported by a controller running with
+	// --dry-run, and cleared by one that is not.
+	// +listType=atomic
+	// +optional
+	PlannedChanges []PlannedChange `json:"plannedChanges,omitempty"`
+
+	// Conditions describe the current state of the HelloWorld
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelloWorldComponentName is the name of the HelloWorldComponent singleton
+const HelloWorldComponentName = "default-helloworldcomponent"
+
+// ManagementState tells whether the opendatahub operator has the component deployed.
+// +kubebuilder:validation:Enum=Managed;Removed
+type ManagementState string
+
+const (
+	// ManagedState deploys the HelloWorlds of the component
+	ManagedState ManagementState = "Managed"
+	// RemovedState deletes the HelloWorlds of the component
+	RemovedState ManagementState = "Removed"
+)
+
+// HelloWorldComponentSpec defines the desired state of the component.
+type HelloWorldComponentSpec struct {
+	// ManagementState is Managed to deploy the HelloWorlds of the component,
+	// Removed to delete them
+	// +kubebuilder:default=Managed
+	// +optional
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
+	// HelloWorlds lists the HelloWorlds the component deploys in the
+	// applications namespace
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	HelloWorlds []ComponentHelloWorld `json:"helloWorlds,omitempty"`
+}
+
+// ComponentHelloWorld is a HelloWorld deployed by the component.
+type ComponentHelloWorld struct {
+	// Name is the name of the HelloWorld
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Spec is the spec of the HelloWorld
+	// +optional
+	Spec HelloWorldSpec `json:"spec,omitempty"`
+}
+
+// ComponentRelease is a release of the component, as reported to the
+// opendatahub operator.
+type ComponentRelease struct {
+	// Name is the name of the released component
+	Name string `json:"name"`
+
+	// Version is the version of the release
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// RepoURL is the repository the release is built from
+	// +optional
+	RepoURL string `json:"repoUrl,omitempty"`
+}
+
+// Phases reported on HelloWorldComponent.
+const (
+	// PhaseReady means every HelloWorld of the component is ready
+	PhaseReady = "Ready"
+	// PhaseNotReady means a HelloWorld of the component is not ready, or the
+	// component is removed
+	PhaseNotReady = "NotReady"
+)
+
+// Condition types reported on HelloWorldComponent, besides Ready.
+const (
+	// ConditionTypeProvisioningSucceeded indicates that every HelloWorld of
+	// the component was created or updated
+	ConditionTypeProvisioningSucceeded = "ProvisioningSucceeded"
+)
+
+// HelloWorldComponentStatus defines the observed state of the component,
+// with the fields the opendatahub operator reads from every component.
+type HelloWorldComponentStatus struct {
+	// Phase is Ready when every HelloWorld of the component is ready, NotReady otherwise
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// ObservedGeneration is the HelloWorldComponent generation the status was computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe the current state of the component
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Releases lists the releases of the component that are deployed
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Releases []ComponentRelease `json:"releases,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'default-helloworldcomponent'",message="HelloWorldComponent name must be default-helloworldcomponent"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Ready"
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`,description="Reason"
+
+// HelloWorldComponent is the Schema for the helloworldcomponents API. It is
+// the singleton through which the opendatahub operator manages the
+// component, as it does the other components of a DataScienceCluster, and
+// owns the HelloWorlds the component deploys.
+type HelloWorldComponent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HelloWorldComponentSpec   `json:"spec,omitempty"`
+	Status HelloWorldComponentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HelloWorldComponentList contains a list of HelloWorldComponent.
+type HelloWorldComponentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HelloWorldComponent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HelloWorldComponent{}, &HelloWorldComponentList{})
+}
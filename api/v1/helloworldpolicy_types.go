@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelloWorldPolicySpec defines the defaults and limits platform admins
+// enforce on the HelloWorlds of their tenants.
+type HelloWorldPolicySpec struct {
+	// NamespaceSelector selects the namespaces whose HelloWorlds the policy
+	// applies to. The policy applies to every namespace if unset.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// AllowedImages lists the registries, or registry/repository prefixes,
+	// HelloWorld images may be pulled from. Any image is allowed if unset.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +optional
+	AllowedImages []string `json:"allowedImages,omitempty"`
+
+	// DefaultImage is the nginx image of HelloWorlds that do not set spec.image
+	// +optional
+	DefaultImage string `json:"defaultImage,omitempty"`
+
+	// MaxReplicas is the most nginx replicas a HelloWorld may ask for
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// RequiredLabels lists the label keys every HelloWorld must set
+	// +listType=set
+	// +optional
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+
+	// AllowedExposures lists the ways HelloWorlds may be exposed. Any
+	// exposure is allowed if unset.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +optional
+	AllowedExposures []ExposureType `json:"allowedExposures,omitempty"`
+
+	// DefaultExposure is the exposure of HelloWorlds that do not set spec.exposure
+	// +optional
+	DefaultExposure ExposureType `json:"defaultExposure,omitempty"`
+
+	// MaxMessageSize is the largest message, in bytes, a HelloWorld may
+	// serve, including its scheduled messages
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxMessageSize *int32 `json:"maxMessageSize,omitempty"`
+
+	// Quota limits the HelloWorlds of each namespace the policy applies to
+	// +optional
+	Quota *NamespaceQuota `json:"quota,omitempty"`
+}
+
+// NamespaceQuota limits the HelloWorlds of a namespace. HelloWorlds are
+// admitted in creation order, so the ones created last are over the quota.
+type NamespaceQuota struct {
+	// MaxHelloWorlds is the most HelloWorlds a namespace may hold
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxHelloWorlds *int32 `json:"maxHelloWorlds,omitempty"`
+
+	// MaxReplicas is the most nginx replicas the HelloWorlds of a namespace
+	// may ask for in total
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// HelloWorldPolicy is the Schema for the helloworldpolicies API. The
+// validating webhook rejects HelloWorlds that break a policy applying to
+// their namespace, and the controller serves them with its defaults.
+type HelloWorldPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HelloWorldPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HelloWorldPolicyList contains a list of HelloWorldPolicy.
+type HelloWorldPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HelloWorldPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HelloWorldPolicy{}, &HelloWorldPolicyList{})
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelloWorldSetSpec defines the HelloWorlds generated from a template.
+// +kubebuilder:validation:XValidation:rule="has(self.instances) || has(self.namespaceSelector)",message="at least one of instances or namespaceSelector must be set"
+type HelloWorldSetSpec struct {
+	// TemplateName is the name of the HelloWorldTemplate the HelloWorlds are generated from
+	// +kubebuilder:validation:MinLength=1
+	TemplateName string `json:"templateName"`
+
+	// Parameters sets parameters of the template for every HelloWorld
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Instances lists HelloWorlds to generate, and sets parameters for them,
+	// including the HelloWorlds generated for the namespaces selected by
+	// NamespaceSelector. Entries naming the same HelloWorld are merged, the
+	// parameters of later ones taking precedence.
+	// +listType=atomic
+	// +optional
+	Instances []HelloWorldSetInstance `json:"instances,omitempty"`
+
+	// NamespaceSelector generates a HelloWorld named after the set in every
+	// namespace it selects
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// HelloWorldSetInstance is a HelloWorld generated by a HelloWorldSet.
+type HelloWorldSetInstance struct {
+	// Namespace is the namespace of the HelloWorld
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the HelloWorld. Defaults to the name of the set.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Parameters sets parameters of the template for this HelloWorld,
+	// overriding those of the set
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// HelloWorldSetInstanceStatus reports the state of a HelloWorld of a HelloWorldSet.
+type HelloWorldSetInstanceStatus struct {
+	// Namespace is the namespace of the HelloWorld
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the HelloWorld
+	Name string `json:"name"`
+
+	// Ready is whether the HelloWorld was generated and is available
+	Ready bool `json:"ready"`
+
+	// Message tells why the HelloWorld is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Condition types reported on HelloWorldSet.
+const (
+	// ConditionTypeReady indicates that every HelloWorld of the set, or of
+	// the HelloWorldComponent, was generated and is available
+	ConditionTypeReady = "Ready"
+)
+
+// HelloWorldSetStatus defines the observed state of HelloWorldSet.
+type HelloWorldSetStatus struct {
+	// ObservedGeneration is the HelloWorldSet generation the status was computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Instances is the number of HelloWorlds the set generates
+	// +optional
+	Instances int32 `json:"instances,omitempty"`
+
+	// ReadyInstances is the number of HelloWorlds of the set that are ready
+	// +optional
+	ReadyInstances int32 `json:"readyInstances,omitempty"`
+
+	// HelloWorlds reports the state of every HelloWorld of the set, in
+	// namespace and name order
+	// +listType=atomic
+	// +optional
+	HelloWorlds []HelloWorldSetInstanceStatus `json:"helloWorlds,omitempty"`
+
+	// Conditions describe the current state of the HelloWorldSet
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Template",type=string,JSONPath=`.spec.templateName`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyInstances`
+// +kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.status.instances`
+
+// HelloWorldSet is the Schema for the helloworldsets API. It generates
+// HelloWorlds from a HelloWorldTemplate, for a list of instances or in the
+// namespaces it selects, and owns them: HelloWorlds it no longer generates
+// are deleted.
+type HelloWorldSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HelloWorldSetSpec   `json:"spec,omitempty"`
+	Status HelloWorldSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HelloWorldSetList contains a list of HelloWorldSet.
+type HelloWorldSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HelloWorldSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HelloWorldSet{}, &HelloWorldSetList{})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelloWorldSummaryName is the name of the HelloWorldSummary of every namespace
+const HelloWorldSummaryName = "helloworlds"
+
+// FailingHelloWorld is a degraded HelloWorld and the condition reporting it.
+type FailingHelloWorld struct {
+	// Name is the name of the HelloWorld
+	Name string `json:"name"`
+
+	// Condition is the type of the condition reporting the HelloWorld as degraded
+	Condition string `json:"condition"`
+
+	// Reason is the reason of the condition
+	Reason string `json:"reason"`
+
+	// Message is the message of the condition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// HelloWorldSummaryStatus rolls up the state of the HelloWorlds of a namespace.
+type HelloWorldSummaryStatus struct {
+	// Total is the number of HelloWorlds in the namespace
+	Total int32 `json:"total"`
+
+	// Ready is the number of HelloWorlds whose current generation is served
+	// and available
+	Ready int32 `json:"ready"`
+
+	// Degraded is the number of HelloWorlds with a condition reporting a
+	// failure, such as a failed rollout, a conflicting child, or a broken
+	// policy or quota
+	Degraded int32 `json:"degraded"`
+
+	// Failing lists the first degraded HelloWorlds in name order, with the
+	// first of their conditions reporting them as degraded
+	// +kubebuilder:validation:MaxItems=50
+	// +listType=atomic
+	// +optional
+	Failing []FailingHelloWorld `json:"failing,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'helloworlds'",message="the HelloWorldSummary of a namespace must be named helloworlds"
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.total`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="Degraded",type=integer,JSONPath=`.status.degraded`
+
+// HelloWorldSummary is the Schema for the helloworldsummaries API. The
+// controller maintains one, named helloworlds, in every namespace holding
+// HelloWorlds, and deletes it once the namespace holds none.
+type HelloWorldSummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status HelloWorldSummaryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HelloWorldSummaryList contains a list of HelloWorldSummary.
+type HelloWorldSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HelloWorldSummary `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HelloWorldSummary{}, &HelloWorldSummaryList{})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelloWorldTemplateSpec defines the HelloWorlds HelloWorldSets stamp out
+// from the template.
+type HelloWorldTemplateSpec struct {
+	// Parameters lists the parameters HelloWorldSets fill in. Every $(name)
+	// of a parameter in the labels, annotations and string fields of the
+	// template is replaced with its value, as are $(metadata.name) and
+	// $(metadata.namespace) with the name and namespace of the HelloWorld.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+
+	// Template is the HelloWorld generated for every instance of a HelloWorldSet
+	Template HelloWorldInstanceTemplate `json:"template"`
+}
+
+// TemplateParameter is a parameter of a HelloWorldTemplate.
+type TemplateParameter struct {
+	// Name is the name of the parameter, referenced as $(name) in the template
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Description tells what the parameter is for
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Default is the value of the parameter when a HelloWorldSet does not set
+	// it. HelloWorldSets must set the parameters without a default.
+	// +optional
+	Default *string `json:"default,omitempty"`
+}
+
+// HelloWorldInstanceTemplate is the HelloWorld generated for every instance
+// of a HelloWorldSet.
+type HelloWorldInstanceTemplate struct {
+	// Labels are set on the generated HelloWorlds
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are set on the generated HelloWorlds
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Spec is the spec of the generated HelloWorlds
+	Spec HelloWorldSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// HelloWorldTemplate is the Schema for the helloworldtemplates API. It is
+// the HelloWorld that HelloWorldSets generate, with parameters filled in for
+// each instance.
+type HelloWorldTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HelloWorldTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HelloWorldTemplateList contains a list of HelloWorldTemplate.
+type HelloWorldTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HelloWorldTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HelloWorldTemplate{}, &HelloWorldTemplateList{})
+}
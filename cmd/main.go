@@ -17,9 +17,14 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -29,18 +34,35 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/certs"
 	"github.com/opendatahub-io/sample-component/internal/controller"
+	"github.com/opendatahub-io/sample-component/internal/health"
+	"github.com/opendatahub-io/sample-component/internal/image"
+	"github.com/opendatahub-io/sample-component/internal/logging"
+	"github.com/opendatahub-io/sample-component/internal/probe"
+	"github.com/opendatahub-io/sample-component/internal/tracing"
+	"github.com/opendatahub-io/sample-component/internal/version"
+	webhookhelloworldv1 "github.com/opendatahub-io/sample-component/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,6 +71,9 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// leaderElectionID names the lease replicas of the manager elect a leader with
+const leaderElectionID = "0e3e0fcd.opendatahub.io"
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -56,6 +81,7 @@ func init() {
 	utilruntime.Must(corev1.AddToScheme(scheme))
 	utilruntime.Must(routev1.Install(scheme))
 	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(helloworldv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
@@ -64,9 +90,36 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
+	var gracefulShutdownTimeout time.Duration
+	var drainTimeout time.Duration
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var allowedRegistries string
+	var dryRun bool
+	var maxConcurrentReconciles int
+	var rateLimitBaseDelay time.Duration
+	var rateLimitMaxDelay time.Duration
+	var rateLimitQPS float64
+	var rateLimitBurst int
+	var reconcileTimeout time.Duration
+	var summaryCoalesceDelay time.Duration
+	var contentProbeInterval time.Duration
+	var enableWebhooks bool
+	var stalledReconcileThreshold time.Duration
+	var enableCertRotation bool
+	var certDir string
+	var certSecretName string
+	var certServiceNames string
+	var certValidatingWebhooks string
+	var certMutatingWebhooks string
+	var certConversionCRDs string
+	var maxHelloWorldsPerNamespace int
+	var applicationsNamespace string
+	var maxReplicasPerNamespace int
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -74,17 +127,106 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"The time standby replicas wait before taking over a leader election lease the leader stopped renewing.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"The time the leader keeps retrying to renew its lease before giving up leadership. "+
+			"Must be shorter than the lease duration.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"The time replicas wait between attempts to acquire or renew the leader election lease. "+
+			"The renew deadline must be longer than 1.2 times it.")
+	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second,
+		"The time the manager waits on shutdown for the reconciles in flight to complete, "+
+			"before releasing its leader election lease.")
+	flag.DurationVar(&drainTimeout, "drain-timeout", 20*time.Second,
+		"The time a reconcile of any controller in flight on shutdown may carry on writing its objects. "+
+			"Must be shorter than the graceful shutdown timeout, so that the lease is only released once it ends.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
-	opts := zap.Options{
-		Development: true,
-	}
+	flag.StringVar(&allowedRegistries, "allowed-registries", "",
+		"Comma-separated list of registries, or registry/repository prefixes, HelloWorld images may be pulled from. "+
+			"Leave empty to allow any registry.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, HelloWorld children are applied with server-side dry-run and the changes that would be made "+
+			"are logged and reported in status.plannedChanges instead of being made.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of HelloWorlds reconciled in parallel.")
+	flag.DurationVar(&rateLimitBaseDelay, "rate-limit-base-delay", 5*time.Millisecond,
+		"The delay before a HelloWorld that failed to reconcile is retried, doubled after every further failure.")
+	flag.DurationVar(&rateLimitMaxDelay, "rate-limit-max-delay", 1000*time.Second,
+		"The longest delay before a HelloWorld that keeps failing to reconcile is retried.")
+	flag.Float64Var(&rateLimitQPS, "rate-limit-qps", 10,
+		"The number of HelloWorld reconciles queued per second once the burst is used up, across all HelloWorlds.")
+	flag.IntVar(&rateLimitBurst, "rate-limit-burst", 100,
+		"The number of HelloWorld reconciles that may be queued at once before rate-limit-qps applies.")
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", 0,
+		"The longest a single reconcile of any controller may take before it is cancelled and retried. "+
+			"Leave as 0 for no timeout.")
+	flag.DurationVar(&summaryCoalesceDelay, "summary-coalesce-delay", 2*time.Second,
+		"How long the HelloWorldSummary of a namespace waits after a change to one of its HelloWorlds before it is "+
+			"updated, so that the changes made meanwhile are rolled up by a single list of the namespace.")
+	flag.DurationVar(&contentProbeInterval, "content-probe-interval", 0,
+		"How often to fetch the page served by each HelloWorld's Service and check it matches the rendered content, "+
+			"reported in the ContentVerified condition. Leave as 0 to disable probing.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the webhook server is started, HelloWorlds are validated against the HelloWorldPolicies applying "+
+			"to their namespace at admission, and the manager is only ready once it serves TLS.")
+	flag.DurationVar(&stalledReconcileThreshold, "stalled-reconcile-threshold", 10*time.Minute,
+		"How long a single reconcile may run before the manager is reported as not live and restarted. "+
+			"Keep it above --reconcile-timeout, which bounds the reconciles it watches for.")
+	flag.BoolVar(&enableCertRotation, "enable-cert-rotation", false,
+		"If set, the manager generates a CA and the serving certificate of the webhook and metrics servers, "+
+			"keeps them in --cert-secret-name, injects the CA into the webhooks that call it and rotates them "+
+			"before they expire. Leave unset to serve certificates mounted into --cert-dir, e.g. by cert-manager.")
+	flag.StringVar(&certDir, "cert-dir", filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"),
+		"The directory the webhook and metrics servers read tls.crt and tls.key from.")
+	flag.StringVar(&certSecretName, "cert-secret-name", "controller-manager-serving-cert",
+		"The Secret, in the namespace of the manager, the rotated certificates are kept in.")
+	flag.StringVar(&certServiceNames, "cert-service-names", "",
+		"Comma-separated list of the Services, in the namespace of the manager, in front of the webhook and "+
+			"metrics servers, that the rotated serving certificate is valid for.")
+	flag.StringVar(&certValidatingWebhooks, "cert-validating-webhooks", "",
+		"Comma-separated list of the ValidatingWebhookConfigurations the rotated CA is injected into.")
+	flag.StringVar(&certMutatingWebhooks, "cert-mutating-webhooks", "",
+		"Comma-separated list of the MutatingWebhookConfigurations the rotated CA is injected into.")
+	flag.StringVar(&certConversionCRDs, "cert-conversion-crds", "",
+		"Comma-separated list of the CustomResourceDefinitions whose conversion webhook the rotated CA is injected into.")
+	flag.IntVar(&maxHelloWorldsPerNamespace, "max-helloworlds-per-namespace", 0,
+		"The most HelloWorlds a namespace may hold, on top of the quotas of HelloWorldPolicies. "+
+			"Leave as 0 for no limit.")
+	flag.IntVar(&maxReplicasPerNamespace, "max-replicas-per-namespace", 0,
+		"The most nginx replicas the HelloWorlds of a namespace may ask for in total, on top of the quotas of "+
+			"HelloWorldPolicies. Leave as 0 for no limit.")
+	flag.StringVar(&applicationsNamespace, "applications-namespace", "opendatahub",
+		"The namespace the HelloWorlds of the HelloWorldComponent are deployed in.")
+	logPreset := logging.DevelopmentPreset
+	flag.Var(&logPreset, "log-preset",
+		"The logging defaults, development for human-readable debug logs, or production for JSON info logs. "+
+			"The zap flags override them. Set the helloworld.opendatahub.io/log-level annotation of a HelloWorld "+
+			"to debug to log its reconciles at debug level.")
+	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
+	logPreset.Apply(&opts, flag.CommandLine)
+
+	ctrl.SetLogger(logging.New(&opts))
+	setupLog.Info("sample-component release", "version", version.Get())
+
+	err := validateShutdownFlags(leaseDuration, renewDeadline, retryPeriod, gracefulShutdownTimeout, drainTimeout)
+	if err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	// Reconciles are traced when the standard OTEL_* environment variables
+	// configure an OTLP exporter
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -101,7 +243,21 @@ func main() {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
+	// The rotator keeps the serving certificate of both servers in certDir;
+	// otherwise the webhook server reads a mounted one from there and the
+	// metrics server generates its own
+	var rotator *certs.Rotator
+	if enableCertRotation {
+		rotator, err = newCertRotator(certDir, certSecretName, splitList(certServiceNames),
+			splitList(certValidatingWebhooks), splitList(certMutatingWebhooks), splitList(certConversionCRDs))
+		if err != nil {
+			setupLog.Error(err, "unable to set up certificate rotation")
+			os.Exit(1)
+		}
+	}
+
 	webhookServer := webhook.NewServer(webhook.Options{
+		CertDir: certDir,
 		TLSOpts: tlsOpts,
 	})
 
@@ -122,56 +278,288 @@ func main() {
 		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.1/pkg/metrics/filters#WithAuthenticationAndAuthorization
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 
-		// TODO(user): If CertDir, CertName, and KeyName are not specified, controller-runtime will automatically
+		// If CertDir, CertName, and KeyName are not specified, controller-runtime will automatically
 		// generate self-signed certificates for the metrics server. While convenient for development and testing,
 		// this setup is not recommended for production.
+		if rotator != nil {
+			metricsServerOptions.CertDir = certDir
+			metricsServerOptions.CertName = certs.CertKey
+			metricsServerOptions.KeyName = certs.KeyKey
+		}
 	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  controller.CacheOptions(),
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "0e3e0fcd.opendatahub.io",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		LeaderElectionID:       leaderElectionID,
+		LeaseDuration:          &leaseDuration,
+		RenewDeadline:          &renewDeadline,
+		RetryPeriod:            &retryPeriod,
+		// The leader steps down once the reconciles in flight are drained, so
+		// that a standby replica takes over without waiting for the lease to
+		// expire. This is safe as the binary ends right after the manager
+		// stops, only flushing traces.
+		LeaderElectionReleaseOnCancel: true,
+		GracefulShutdownTimeout:       &gracefulShutdownTimeout,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// The servers fail to start without certificates, so the first ones are
+	// generated before the manager starts
+	if rotator != nil {
+		if err := rotator.Ensure(ctrl.LoggerInto(context.Background(), setupLog)); err != nil {
+			setupLog.Error(err, "unable to generate certificates")
+			os.Exit(1)
+		}
+		if err := mgr.Add(rotator); err != nil {
+			setupLog.Error(err, "unable to set up certificate rotation")
+			os.Exit(1)
+		}
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	enableRoutes, err := routeAPIAvailable(dc)
+	if err != nil {
+		setupLog.Error(err, "unable to discover the route.openshift.io API")
+		os.Exit(1)
+	}
+	if !enableRoutes {
+		setupLog.Info("route.openshift.io API not found, HelloWorlds will not be exposed through Routes")
+	}
+	if dryRun {
+		setupLog.Info("running in dry-run mode, HelloWorld children will not be changed")
+	}
+	quota := &helloworldv1.NamespaceQuota{}
+	if maxHelloWorldsPerNamespace > 0 {
+		quota.MaxHelloWorlds = ptr.To(int32(maxHelloWorldsPerNamespace))
+	}
+	if maxReplicasPerNamespace > 0 {
+		quota.MaxReplicas = ptr.To(int32(maxReplicasPerNamespace))
+	}
+
 	if err = (&controller.HelloWorldReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		Recorder:          mgr.GetEventRecorderFor("helloworld-controller"),
+		APIReader:         mgr.GetAPIReader(),
+		Resolver:          &image.RegistryResolver{},
+		Clock:             clock.RealClock{},
+		AllowedRegistries: splitList(allowedRegistries),
+		EnableRoutes:      enableRoutes,
+		DryRun:            dryRun,
+		Prober:            &probe.HTTPProber{},
+		ProbeInterval:     contentProbeInterval,
+		Quota:             quota,
+
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		RateLimiter: controller.NewRateLimiter(
+			rateLimitBaseDelay, rateLimitMaxDelay, rateLimitQPS, rateLimitBurst),
+		ReconcileTimeout: reconcileTimeout,
+		DrainTimeout:     drainTimeout,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HelloWorld")
 		os.Exit(1)
 	}
-	// +kubebuilder:scaffold:builder
+	if err = (&controller.HelloWorldSetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("helloworldset-controller"),
+
+		ReconcileTimeout: reconcileTimeout,
+		DrainTimeout:     drainTimeout,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HelloWorldSet")
+		os.Exit(1)
+	}
+	if err = (&controller.HelloWorldSummaryReconciler{
+		Client:        mgr.GetClient(),
+		CoalesceDelay: summaryCoalesceDelay,
 
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up health check")
+		ReconcileTimeout: reconcileTimeout,
+		DrainTimeout:     drainTimeout,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HelloWorldSummary")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
+	if err = (&controller.HelloWorldComponentReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		Recorder:              mgr.GetEventRecorderFor("helloworldcomponent-controller"),
+		ApplicationsNamespace: applicationsNamespace,
+		Releases: []helloworldv1.ComponentRelease{{
+			Name:    "sample-component",
+			Version: version.Get(),
+			RepoURL: version.RepoURL,
+		}},
+
+		ReconcileTimeout: reconcileTimeout,
+		DrainTimeout:     drainTimeout,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HelloWorldComponent")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		if err = webhookhelloworldv1.SetupHelloWorldWebhookWithManager(mgr, controller.DefaultHelloWorldImage, quota); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HelloWorld")
+			os.Exit(1)
+		}
+	}
+	// +kubebuilder:scaffold:builder
+
+	// The manager is live as long as reconciles do not get stuck, and ready
+	// once it can serve: its caches have synced, the APIs it manages are
+	// served, and it leads or another replica does
+	requiredAPIs := []schema.GroupVersion{helloworldv1.GroupVersion, corev1.SchemeGroupVersion, appsv1.SchemeGroupVersion}
+	if enableRoutes {
+		requiredAPIs = append(requiredAPIs, routev1.GroupVersion)
+	}
+	healthChecks := map[string]healthz.Checker{
+		"healthz": healthz.Ping,
+	}
+	for _, name := range []string{"helloworld", "helloworldset", "helloworldsummary", "helloworldcomponent"} {
+		healthChecks["workqueue-"+name] = health.Workqueue(metrics.Registry, name, stalledReconcileThreshold)
+	}
+	readyChecks := map[string]healthz.Checker{
+		"readyz":     healthz.Ping,
+		"cache-sync": health.CacheSync(mgr.GetCache(), time.Second),
+		"api-groups": health.APIGroups(dc, requiredAPIs...),
+	}
+	if enableLeaderElection {
+		namespace, err := inClusterNamespace()
+		if err != nil {
+			setupLog.Error(err, "unable to find the leader election namespace")
+			os.Exit(1)
+		}
+		lease := client.ObjectKey{Name: leaderElectionID, Namespace: namespace}
+		readyChecks["leader-election"] = health.LeaderElection(mgr.Elected(), mgr.GetAPIReader(), lease, clock.RealClock{})
+	}
+	if enableWebhooks {
+		readyChecks["webhook"] = mgr.GetWebhookServer().StartedChecker()
+	}
+	for name, check := range healthChecks {
+		if err := mgr.AddHealthzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up health check", "check", name)
+			os.Exit(1)
+		}
+	}
+	for name, check := range readyChecks {
+		if err := mgr.AddReadyzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", name)
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if shutdownErr := shutdownTracing(shutdownCtx); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "unable to flush traces")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// validateShutdownFlags checks that the leader election timings can be
+// honoured, and that reconciles are drained before the manager gives up
+// waiting for them and releases its lease to another replica.
+func validateShutdownFlags(
+	leaseDuration, renewDeadline, retryPeriod, gracefulShutdownTimeout, drainTimeout time.Duration,
+) error {
+	switch {
+	case renewDeadline >= leaseDuration:
+		return fmt.Errorf("--leader-elect-renew-deadline %s must be shorter than --leader-elect-lease-duration %s",
+			renewDeadline, leaseDuration)
+	case float64(renewDeadline) <= leaderelection.JitterFactor*float64(retryPeriod):
+		return fmt.Errorf("--leader-elect-renew-deadline %s must be longer than %.1f times --leader-elect-retry-period %s",
+			renewDeadline, leaderelection.JitterFactor, retryPeriod)
+	case drainTimeout >= gracefulShutdownTimeout:
+		return fmt.Errorf("--drain-timeout %s must be shorter than --graceful-shutdown-timeout %s",
+			drainTimeout, gracefulShutdownTimeout)
+	}
+	return nil
+}
+
+// routeAPIAvailable reports whether the cluster serves OpenShift Routes.
+func routeAPIAvailable(dc discovery.DiscoveryInterface) (bool, error) {
+	_, err := dc.ServerResourcesForGroupVersion(routev1.GroupVersion.String())
+	if k8serr.IsNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// newCertRotator returns a Rotator keeping the serving certificate of the
+// services in a Secret of the namespace of the manager.
+func newCertRotator(certDir, secretName string, services, validatingWebhooks, mutatingWebhooks, crds []string,
+) (*certs.Rotator, error) {
+	if len(services) == 0 {
+		return nil, fmt.Errorf("--cert-service-names must name at least one Service")
+	}
+	namespace, err := inClusterNamespace()
+	if err != nil {
+		return nil, err
+	}
+	// The rotator only reads a few objects, now and then, so it does not
+	// wait for the cache of the manager nor fill it with every Secret
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	var dnsNames []string
+	for _, service := range services {
+		dnsNames = append(dnsNames,
+			fmt.Sprintf("%s.%s.svc", service, namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace))
+	}
+
+	return &certs.Rotator{
+		Client:             c,
+		Secret:             client.ObjectKey{Name: secretName, Namespace: namespace},
+		DNSNames:           dnsNames,
+		CertDir:            certDir,
+		ValidatingWebhooks: validatingWebhooks,
+		MutatingWebhooks:   mutatingWebhooks,
+		CRDs:               crds,
+		Clock:              clock.RealClock{},
+	}, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// inClusterNamespace returns the namespace the manager runs in, where it
+// holds its leader election lease.
+func inClusterNamespace() (string, error) {
+	namespace, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(namespace)), nil
+}
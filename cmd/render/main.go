@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command render prints the objects the HelloWorld controller would create
+// for the HelloWorlds in a YAML or JSON file, without a cluster.
+//
+//	render -f helloworld.yaml
+//	kubectl get helloworld my-hello -o yaml | render -o json
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/controller"
+	"github.com/opendatahub-io/sample-component/internal/image"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(helloworldv1.AddToScheme(scheme))
+}
+
+func main() {
+	var filename string
+	var output string
+	var namespace string
+	var at string
+	var routes bool
+	var resolve bool
+	flag.StringVar(&filename, "f", "-", "The file to read HelloWorld YAML or JSON from, - for stdin.")
+	flag.StringVar(&output, "o", "yaml", "The output format, yaml or json.")
+	flag.StringVar(&namespace, "n", "default", "The namespace of HelloWorlds that do not set one.")
+	flag.StringVar(&at, "at", "", "The RFC 3339 time to evaluate schedules at. Defaults to now.")
+	flag.BoolVar(&routes, "routes", true, "If set, render Routes as on clusters serving route.openshift.io.")
+	flag.BoolVar(&resolve, "resolve-digests", false,
+		"If set, resolve image tags to digests from their registries as the controller does.")
+	flag.Parse()
+
+	opts := controller.RenderOptions{
+		Now:          time.Now(),
+		EnableRoutes: routes,
+	}
+	if at != "" {
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			fail(fmt.Errorf("invalid -at time: %w", err))
+		}
+		opts.Now = t
+	}
+	if resolve {
+		opts.Resolver = &image.RegistryResolver{}
+	}
+	if output != "yaml" && output != "json" {
+		fail(fmt.Errorf("unsupported output format %q", output))
+	}
+
+	in := io.Reader(os.Stdin)
+	if filename != "-" {
+		f, err := os.Open(filename)
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close() //nolint:errcheck
+		in = f
+	}
+
+	hws, err := readHelloWorlds(in, namespace)
+	if err != nil {
+		fail(err)
+	}
+
+	var objs []client.Object
+	for _, hw := range hws {
+		rendered, err := controller.RenderHelloWorld(context.Background(), scheme, hw, opts)
+		if err != nil {
+			fail(fmt.Errorf("failed to render HelloWorld %s: %w", hw.Name, err))
+		}
+		objs = append(objs, rendered...)
+	}
+
+	out, err := encode(objs, output)
+	if err != nil {
+		fail(err)
+	}
+	_, err = os.Stdout.Write(out)
+	if err != nil {
+		fail(err)
+	}
+}
+
+// readHelloWorlds decodes every HelloWorld in a stream of YAML documents or
+// JSON objects, skipping empty documents.
+func readHelloWorlds(in io.Reader, namespace string) ([]*helloworldv1.HelloWorld, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(in, 4096)
+
+	var hws []*helloworldv1.HelloWorld
+	for {
+		hw := &helloworldv1.HelloWorld{}
+		err := decoder.Decode(hw)
+		if errors.Is(err, io.EOF) {
+			return hws, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hw.Kind == "" && hw.Name == "" {
+			continue
+		}
+		gvk := helloworldv1.GroupVersion.WithKind("HelloWorld")
+		if hw.GroupVersionKind() != gvk {
+			return nil, fmt.Errorf("expected a %s, got %s %s", gvk, hw.GroupVersionKind(), hw.Name)
+		}
+		if hw.Namespace == "" {
+			hw.Namespace = namespace
+		}
+		hws = append(hws, hw)
+	}
+}
+
+// encode formats objects as multi-document YAML, or as a JSON List.
+func encode(objs []client.Object, output string) ([]byte, error) {
+	if output == "json" {
+		list := &corev1.List{}
+		list.APIVersion = "v1"
+		list.Kind = "List"
+		for _, obj := range objs {
+			list.Items = append(list.Items, runtime.RawExtension{Object: obj})
+		}
+		out, err := json.MarshalIndent(list, "", "  ")
+		return append(out, '\n'), err
+	}
+
+	var out bytes.Buffer
+	for _, obj := range objs {
+		doc, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString("---\n")
+		out.Write(doc)
+	}
+	return out.Bytes(), nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certs generates a CA and serving certificates for the webhook and
+// metrics servers, keeps them in a Secret, and rotates them before they
+// expire, so the manager does not need cert-manager to serve TLS in-cluster.
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// Keys of the certificates and keys in the Secret and in the certificate
+// directory. tls.crt and tls.key are the serving certificate and its key,
+// the names controller-runtime servers read by default.
+const (
+	CACertKey = "ca.crt"
+	CAKeyKey  = "ca.key"
+	CertKey   = "tls.crt"
+	KeyKey    = "tls.key"
+)
+
+// clockSkew backdates certificates, so they are valid on nodes whose clock
+// is slightly behind.
+const clockSkew = time.Hour
+
+// KeyPair is a PEM encoded certificate and private key.
+type KeyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// parse decodes the certificate and key of a key pair.
+func (p KeyPair) parse() (*x509.Certificate, crypto.Signer, error) {
+	certBlock, _ := pem.Decode(p.Cert)
+	if certBlock == nil || certBlock.Type != "CERTIFICATE" {
+		return nil, nil, errors.New("no PEM encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyBlock, _ := pem.Decode(p.Key)
+	if keyBlock == nil || keyBlock.Type != "EC PRIVATE KEY" {
+		return nil, nil, errors.New("no PEM encoded EC private key")
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, nil, errors.New("private key does not match the certificate")
+	}
+
+	return cert, key, nil
+}
+
+// NewCA generates a self-signed CA valid from now for validity.
+func NewCA(commonName string, now time.Time, validity time.Duration) (KeyPair, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	return newKeyPair(template, nil, nil)
+}
+
+// NewServingCert generates a certificate for serving TLS on dnsNames, signed
+// by ca and valid from now for validity, or until ca expires if earlier.
+func NewServingCert(ca KeyPair, dnsNames []string, now time.Time, validity time.Duration) (KeyPair, error) {
+	if len(dnsNames) == 0 {
+		return KeyPair{}, errors.New("serving certificates need at least one DNS name")
+	}
+	caCert, caKey, err := ca.parse()
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("invalid CA: %w", err)
+	}
+
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    now.Add(validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if template.NotAfter.After(caCert.NotAfter) {
+		template.NotAfter = caCert.NotAfter
+	}
+
+	return newKeyPair(template, caCert, caKey)
+}
+
+// newKeyPair generates a key and a certificate from template signed by
+// parent, or self-signed if parent is nil.
+func newKeyPair(template, parent *x509.Certificate, parentKey crypto.Signer) (KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return KeyPair{}, err
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	return KeyPair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// splitBundle splits a CA bundle into its first certificate, the current
+// CA, and the certificates after it that are still valid at now, the CAs it
+// replaced.
+func splitBundle(bundle []byte, now time.Time) (current, previous []byte) {
+	block, rest := pem.Decode(bundle)
+	if block == nil {
+		return nil, nil
+	}
+	return pem.EncodeToMemory(block), unexpired(rest, now)
+}
+
+// unexpired returns the PEM encoded certificates of bundle that are valid at
+// now.
+func unexpired(bundle []byte, now time.Time) []byte {
+	var result []byte
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			return result
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err == nil && now.Before(cert.NotAfter) {
+			result = append(result, pem.EncodeToMemory(block)...)
+		}
+	}
+}
+
+// validCA checks that ca is a CA valid until at least deadline.
+func validCA(ca KeyPair, deadline time.Time) error {
+	cert, _, err := ca.parse()
+	if err != nil {
+		return err
+	}
+	if !cert.IsCA {
+		return errors.New("certificate is not a CA")
+	}
+	if cert.NotAfter.Before(deadline) {
+		return fmt.Errorf("CA expires at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// validServingCert checks that cert is signed by ca, serves every DNS name
+// and is valid until at least deadline.
+func validServingCert(cert, ca KeyPair, dnsNames []string, deadline time.Time) error {
+	parsed, _, err := cert.parse()
+	if err != nil {
+		return err
+	}
+	if parsed.NotAfter.Before(deadline) {
+		return fmt.Errorf("certificate expires at %s", parsed.NotAfter.Format(time.RFC3339))
+	}
+	for _, name := range dnsNames {
+		if !slices.Contains(parsed.DNSNames, name) {
+			return fmt.Errorf("certificate does not serve %s", name)
+		}
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca.Cert) {
+		return errors.New("no PEM encoded CA certificate")
+	}
+	_, err = parsed.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: parsed.NotBefore.Add(clockSkew),
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("certificate is not signed by the CA: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Certificates", func() {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	dnsNames := []string{"webhook.system.svc", "webhook.system.svc.cluster.local"}
+
+	It("generates serving certificates signed by the CA", func() {
+		ca, err := NewCA("test-ca", now, 365*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validCA(ca, now)).To(Succeed())
+
+		cert, err := NewServingCert(ca, dnsNames, now, 90*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validServingCert(cert, ca, dnsNames, now.Add(89*24*time.Hour))).To(Succeed())
+
+		Expect(validServingCert(cert, ca, dnsNames, now.Add(91*24*time.Hour))).
+			To(MatchError(ContainSubstring("certificate expires at")))
+		Expect(validServingCert(cert, ca, append(dnsNames, "metrics.system.svc"), now)).
+			To(MatchError("certificate does not serve metrics.system.svc"))
+		Expect(validCA(cert, now)).To(MatchError("certificate is not a CA"))
+	})
+
+	It("does not outlive the CA", func() {
+		ca, err := NewCA("test-ca", now, 30*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		cert, err := NewServingCert(ca, dnsNames, now, 90*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		parsed, _, err := cert.parse()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.NotAfter).To(Equal(now.Add(30 * 24 * time.Hour)))
+	})
+
+	It("rejects certificates signed by another CA", func() {
+		ca, err := NewCA("test-ca", now, 365*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		other, err := NewCA("other-ca", now, 365*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		cert, err := NewServingCert(other, dnsNames, now, 90*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validServingCert(cert, ca, dnsNames, now)).
+			To(MatchError(ContainSubstring("certificate is not signed by the CA")))
+	})
+
+	It("rejects keys that do not match the certificate", func() {
+		ca, err := NewCA("test-ca", now, 365*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		other, err := NewCA("other-ca", now, 365*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(validCA(KeyPair{Cert: ca.Cert, Key: other.Key}, now)).
+			To(MatchError("private key does not match the certificate"))
+		Expect(validCA(KeyPair{}, now)).To(MatchError("no PEM encoded certificate"))
+	})
+})
@@ -0,0 +1,325 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// DefaultCAValidity is how long generated CAs are valid for
+	DefaultCAValidity = 5 * 365 * 24 * time.Hour
+	// DefaultCertValidity is how long generated serving certificates are valid for
+	DefaultCertValidity = 90 * 24 * time.Hour
+	// DefaultRotateBefore is how long before they expire certificates are rotated
+	DefaultRotateBefore = 30 * 24 * time.Hour
+	// DefaultCheckInterval is how often the certificates are checked
+	DefaultCheckInterval = time.Hour
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations;mutatingwebhookconfigurations,verbs=get;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;patch
+
+// Rotator keeps a CA and a serving certificate signed by it in a Secret,
+// regenerating them before they expire. Every replica of the manager writes
+// them to its certificate directory, where the webhook and metrics servers
+// read them from, and injects the CA into the webhook configurations and CRD
+// conversion webhooks that call those servers. Replicas racing to rotate
+// settle on whichever Secret is written first.
+//
+// The CA certificate in the Secret is a bundle: the current CA, followed by
+// the CAs it replaced until they expire. Replicas serve the certificates a
+// replaced CA signed until they next check the Secret, and clients keep
+// trusting them meanwhile.
+type Rotator struct {
+	// Client reads and writes the Secret, webhook configurations and CRDs.
+	// It should not be cached, which would watch every Secret.
+	Client client.Client
+	// Secret holds the CA and serving certificate
+	Secret client.ObjectKey
+	// DNSNames are the names the serving certificate is valid for, those of
+	// the Services in front of the webhook and metrics servers
+	DNSNames []string
+	// CertDir is where the serving certificate and key are written, as
+	// tls.crt and tls.key, next to the CA as ca.crt
+	CertDir string
+
+	// ValidatingWebhooks and MutatingWebhooks name the webhook
+	// configurations to inject the CA into
+	ValidatingWebhooks []string
+	MutatingWebhooks   []string
+	// CRDs name the CustomResourceDefinitions whose conversion webhook the
+	// CA is injected into
+	CRDs []string
+
+	// CAValidity and CertValidity are how long the generated CA and serving
+	// certificates are valid for, DefaultCAValidity and DefaultCertValidity
+	// if zero
+	CAValidity   time.Duration
+	CertValidity time.Duration
+	// RotateBefore is how long before they expire certificates are rotated,
+	// DefaultRotateBefore if zero
+	RotateBefore time.Duration
+	// CheckInterval is how often Start checks the certificates,
+	// DefaultCheckInterval if zero
+	CheckInterval time.Duration
+	// Clock tells the time certificates are checked and generated at
+	Clock clock.PassiveClock
+}
+
+var _ manager.LeaderElectionRunnable = &Rotator{}
+
+// Start implements manager.Runnable, checking the certificates every
+// CheckInterval until ctx is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("cert-rotator")
+	ticker := time.NewTicker(orDefault(r.CheckInterval, DefaultCheckInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := r.Ensure(ctx); err != nil {
+			logger.Error(err, "Failed to rotate certificates")
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every
+// replica serves with the certificates, so every replica keeps them.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Ensure makes sure the Secret holds a CA and serving certificate valid for
+// longer than RotateBefore, writes them to the certificate directory and
+// injects the CA. Call it before the servers start, since they fail to
+// start without certificates.
+func (r *Rotator) Ensure(ctx context.Context) error {
+	secret, err := r.ensureSecret(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update Secret %s: %w", r.Secret, err)
+	}
+
+	err = r.writeFiles(secret)
+	if err != nil {
+		return fmt.Errorf("failed to write certificates to %s: %w", r.CertDir, err)
+	}
+
+	return r.injectCA(ctx, secret.Data[CACertKey])
+}
+
+// ensureSecret returns the Secret, after regenerating the certificates that
+// are missing, invalid or about to expire.
+func (r *Rotator) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return k8serr.IsConflict(err) || k8serr.IsAlreadyExists(err)
+	}, func() error {
+		err := r.Client.Get(ctx, r.Secret, secret)
+		if k8serr.IsNotFound(err) {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: r.Secret.Name, Namespace: r.Secret.Namespace},
+				Type:       corev1.SecretTypeTLS,
+			}
+		} else if err != nil {
+			return err
+		}
+
+		changed, err := r.refresh(ctx, secret)
+		if err != nil || !changed {
+			return err
+		}
+		if secret.ResourceVersion == "" {
+			return r.Client.Create(ctx, secret)
+		}
+		return r.Client.Update(ctx, secret)
+	})
+
+	return secret, err
+}
+
+// refresh regenerates the certificates of secret that need it and drops the
+// expired CAs from its bundle, reporting whether it changed anything. A new
+// CA comes with a new serving certificate.
+func (r *Rotator) refresh(ctx context.Context, secret *corev1.Secret) (bool, error) {
+	logger := log.FromContext(ctx).WithName("cert-rotator")
+	now := r.Clock.Now()
+	deadline := now.Add(orDefault(r.RotateBefore, DefaultRotateBefore))
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	current, previous := splitBundle(secret.Data[CACertKey], now)
+	ca := KeyPair{Cert: current, Key: secret.Data[CAKeyKey]}
+	cert := KeyPair{Cert: secret.Data[CertKey], Key: secret.Data[KeyKey]}
+	renewCA := validCA(ca, deadline)
+	renewCert := renewCA
+	if renewCert == nil {
+		renewCert = validServingCert(cert, ca, r.DNSNames, deadline)
+	}
+	if renewCert == nil {
+		bundle := append(slices.Clone(ca.Cert), previous...)
+		if bytes.Equal(bundle, secret.Data[CACertKey]) {
+			return false, nil
+		}
+		logger.Info("Dropping expired CAs from the CA bundle", "secret", r.Secret)
+		secret.Data[CACertKey] = bundle
+		return true, nil
+	}
+
+	var err error
+	if renewCA != nil {
+		logger.Info("Generating CA", "secret", r.Secret, "reason", renewCA.Error())
+		previous = append(unexpired(ca.Cert, now), previous...)
+		ca, err = NewCA("helloworld-controller-ca", now, orDefault(r.CAValidity, DefaultCAValidity))
+		if err != nil {
+			return false, err
+		}
+	}
+	logger.Info("Generating serving certificate", "secret", r.Secret, "reason", renewCert.Error())
+	cert, err = NewServingCert(ca, r.DNSNames, now, orDefault(r.CertValidity, DefaultCertValidity))
+	if err != nil {
+		return false, err
+	}
+
+	secret.Data[CACertKey], secret.Data[CAKeyKey] = append(slices.Clone(ca.Cert), previous...), ca.Key
+	secret.Data[CertKey], secret.Data[KeyKey] = cert.Cert, cert.Key
+
+	return true, nil
+}
+
+// writeFiles writes the CA and serving certificate of secret to the
+// certificate directory. Files are replaced by renaming, so the servers
+// never read a partially written one.
+func (r *Rotator) writeFiles(secret *corev1.Secret) error {
+	err := os.MkdirAll(r.CertDir, 0o700)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range []string{CACertKey, KeyKey, CertKey} {
+		path := filepath.Join(r.CertDir, key)
+		current, err := os.ReadFile(path)
+		if err == nil && bytes.Equal(current, secret.Data[key]) {
+			continue
+		}
+
+		tmp := path + ".tmp"
+		err = os.WriteFile(tmp, secret.Data[key], 0o600)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(tmp, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// injectCA sets the CA bundle of the webhook configurations and of the
+// conversion webhooks of the CRDs to ca, the bundle of the Secret.
+func (r *Rotator) injectCA(ctx context.Context, ca []byte) error {
+	for _, name := range r.ValidatingWebhooks {
+		cfg := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		err := r.patch(ctx, name, cfg, func() {
+			for i := range cfg.Webhooks {
+				cfg.Webhooks[i].ClientConfig.CABundle = ca
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to inject CA into ValidatingWebhookConfiguration %s: %w", name, err)
+		}
+	}
+
+	for _, name := range r.MutatingWebhooks {
+		cfg := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		err := r.patch(ctx, name, cfg, func() {
+			for i := range cfg.Webhooks {
+				cfg.Webhooks[i].ClientConfig.CABundle = ca
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to inject CA into MutatingWebhookConfiguration %s: %w", name, err)
+		}
+	}
+
+	for _, name := range r.CRDs {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		err := r.patch(ctx, name, crd, func() {
+			conversion := crd.Spec.Conversion
+			if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter ||
+				conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+				return
+			}
+			conversion.Webhook.ClientConfig.CABundle = ca
+		})
+		if err != nil {
+			return fmt.Errorf("failed to inject CA into CustomResourceDefinition %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// patch gets the named cluster-scoped object, applies mutate to it and
+// patches it if that changed anything.
+func (r *Rotator) patch(ctx context.Context, name string, obj client.Object, mutate func()) error {
+	err := r.Client.Get(ctx, client.ObjectKey{Name: name}, obj)
+	if err != nil {
+		return err
+	}
+
+	original := obj.DeepCopyObject().(client.Object)
+	mutate()
+	patch := client.MergeFrom(original)
+	data, err := patch.Data(obj)
+	if err != nil || string(data) == "{}" {
+		return err
+	}
+
+	return r.Client.Patch(ctx, obj, patch)
+}
+
+func orDefault(d, def time.Duration) time.Duration {
+	if d == 0 {
+		return def
+	}
+	return d
+}
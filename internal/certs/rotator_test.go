@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Rotator", func() {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	key := client.ObjectKey{Name: "serving-cert", Namespace: "system"}
+
+	var cli client.Client
+	var clock *clocktesting.FakePassiveClock
+	var rotator *Rotator
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+		webhookClientConfig := admissionregistrationv1.WebhookClientConfig{
+			Service: &admissionregistrationv1.ServiceReference{Name: "webhook", Namespace: "system"},
+		}
+		cli = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "validating"},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{
+					{Name: "a.helloworld.opendatahub.io", ClientConfig: webhookClientConfig},
+					{Name: "b.helloworld.opendatahub.io", ClientConfig: webhookClientConfig},
+				},
+			},
+			&admissionregistrationv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "mutating"},
+				Webhooks: []admissionregistrationv1.MutatingWebhook{
+					{Name: "a.helloworld.opendatahub.io", ClientConfig: webhookClientConfig},
+				},
+			},
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "converted.helloworld.opendatahub.io"},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.WebhookConverter,
+						Webhook: &apiextensionsv1.WebhookConversion{
+							ClientConfig: &apiextensionsv1.WebhookClientConfig{},
+						},
+					},
+				},
+			},
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "helloworlds.helloworld.opendatahub.io"},
+			},
+		).Build()
+		clock = clocktesting.NewFakePassiveClock(now)
+		rotator = &Rotator{
+			Client:             cli,
+			Secret:             key,
+			DNSNames:           []string{"webhook.system.svc"},
+			CertDir:            filepath.Join(GinkgoT().TempDir(), "certs"),
+			ValidatingWebhooks: []string{"validating"},
+			MutatingWebhooks:   []string{"mutating"},
+			CRDs:               []string{"converted.helloworld.opendatahub.io", "helloworlds.helloworld.opendatahub.io"},
+			Clock:              clock,
+		}
+	})
+
+	getSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(cli.Get(ctx, key, secret)).To(Succeed())
+		return secret
+	}
+
+	expectInjected := func(ca []byte) {
+		validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "validating"}, validating)).To(Succeed())
+		for _, webhook := range validating.Webhooks {
+			Expect(webhook.ClientConfig.CABundle).To(Equal(ca))
+		}
+		mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "mutating"}, mutating)).To(Succeed())
+		Expect(mutating.Webhooks[0].ClientConfig.CABundle).To(Equal(ca))
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "converted.helloworld.opendatahub.io"}, crd)).To(Succeed())
+		Expect(crd.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal(ca))
+	}
+
+	expectFiles := func(secret *corev1.Secret) {
+		for _, name := range []string{CACertKey, CertKey, KeyKey} {
+			Expect(os.ReadFile(filepath.Join(rotator.CertDir, name))).To(Equal(secret.Data[name]))
+		}
+		Expect(filepath.Join(rotator.CertDir, CAKeyKey)).NotTo(BeAnExistingFile())
+	}
+
+	It("generates the certificates into a Secret and injects the CA", func() {
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+
+		secret := getSecret()
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		ca := KeyPair{Cert: secret.Data[CACertKey], Key: secret.Data[CAKeyKey]}
+		cert := KeyPair{Cert: secret.Data[CertKey], Key: secret.Data[KeyKey]}
+		Expect(validCA(ca, now.Add(DefaultCAValidity-time.Hour))).To(Succeed())
+		Expect(validServingCert(cert, ca, rotator.DNSNames, now.Add(DefaultCertValidity-time.Hour))).To(Succeed())
+		expectFiles(secret)
+		expectInjected(ca.Cert)
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "helloworlds.helloworld.opendatahub.io"}, crd)).To(Succeed())
+		Expect(crd.Spec.Conversion).To(BeNil())
+	})
+
+	It("keeps certificates that are not about to expire", func() {
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		secret := getSecret()
+
+		clock.SetTime(now.Add(DefaultCertValidity - DefaultRotateBefore - time.Hour))
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		Expect(getSecret()).To(Equal(secret))
+	})
+
+	It("rotates the serving certificate before it expires", func() {
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		secret := getSecret()
+
+		clock.SetTime(now.Add(DefaultCertValidity - DefaultRotateBefore + time.Hour))
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+
+		rotated := getSecret()
+		Expect(rotated.Data[CACertKey]).To(Equal(secret.Data[CACertKey]))
+		Expect(rotated.Data[CertKey]).NotTo(Equal(secret.Data[CertKey]))
+		expectFiles(rotated)
+	})
+
+	It("rotates the CA before it expires", func() {
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		secret := getSecret()
+
+		clock.SetTime(now.Add(DefaultCAValidity - DefaultRotateBefore + time.Hour))
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+
+		rotated := getSecret()
+		Expect(rotated.Data[CACertKey]).NotTo(Equal(secret.Data[CACertKey]))
+		Expect(rotated.Data[CertKey]).NotTo(Equal(secret.Data[CertKey]))
+		expectFiles(rotated)
+		expectInjected(rotated.Data[CACertKey])
+
+		By("trusting the certificates of both CAs until the old one expires")
+		Expect(rotated.Data[CACertKey]).To(HaveSuffix(string(secret.Data[CACertKey])))
+		bundle := KeyPair{Cert: rotated.Data[CACertKey]}
+		Expect(validServingCert(KeyPair{Cert: secret.Data[CertKey], Key: secret.Data[KeyKey]},
+			bundle, rotator.DNSNames, now)).To(Succeed())
+		Expect(validServingCert(KeyPair{Cert: rotated.Data[CertKey], Key: rotated.Data[KeyKey]},
+			bundle, rotator.DNSNames, now)).To(Succeed())
+	})
+
+	It("drops a replaced CA from the bundle once it expires", func() {
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		clock.SetTime(now.Add(DefaultCAValidity - DefaultRotateBefore + time.Hour))
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		rotated := getSecret()
+
+		clock.SetTime(now.Add(DefaultCAValidity - time.Hour))
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		Expect(getSecret()).To(Equal(rotated))
+
+		clock.SetTime(now.Add(DefaultCAValidity + time.Hour))
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		pruned := getSecret()
+		current, _ := splitBundle(rotated.Data[CACertKey], now)
+		Expect(pruned.Data[CACertKey]).To(Equal(current))
+		Expect(pruned.Data[CertKey]).To(Equal(rotated.Data[CertKey]))
+		expectFiles(pruned)
+		expectInjected(current)
+	})
+
+	It("regenerates the serving certificate for new DNS names", func() {
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		secret := getSecret()
+
+		rotator.DNSNames = append(rotator.DNSNames, "metrics.system.svc")
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+
+		rotated := getSecret()
+		Expect(rotated.Data[CACertKey]).To(Equal(secret.Data[CACertKey]))
+		Expect(validServingCert(KeyPair{Cert: rotated.Data[CertKey], Key: rotated.Data[KeyKey]},
+			KeyPair{Cert: rotated.Data[CACertKey]}, rotator.DNSNames, now)).To(Succeed())
+	})
+
+	It("serves the certificates another replica generated", func() {
+		other := *rotator
+		other.CertDir = filepath.Join(GinkgoT().TempDir(), "other")
+		Expect(other.Ensure(ctx)).To(Succeed())
+
+		Expect(rotator.Ensure(ctx)).To(Succeed())
+		expectFiles(getSecret())
+	})
+
+	It("fails when a webhook configuration does not exist", func() {
+		rotator.ValidatingWebhooks = []string{"missing"}
+		Expect(rotator.Ensure(ctx)).To(MatchError(ContainSubstring(
+			"failed to inject CA into ValidatingWebhookConfiguration missing")))
+	})
+})
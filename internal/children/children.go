@@ -0,0 +1,650 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package children reconciles the objects a custom resource owns. Each kind
+// of child is declared once as a Child, with a builder for its desired state
+// and a function that copies the managed fields onto existing objects. A Set
+// of children then applies, compares and prunes them for a parent, and
+// reports one status condition per child kind.
+package children
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/opendatahub-io/sample-component/internal/names"
+	"github.com/opendatahub-io/sample-component/internal/tracing"
+)
+
+// Child reconciles the objects of one kind that a parent owns. T is the state
+// the parent's reconciler derives before reconciling its children, such as
+// the parent object itself and anything computed from its spec.
+type Child[T any, O client.Object] struct {
+	// Kind names the child in errors and in its condition type, <Kind>Ready
+	Kind string
+	// New returns an empty object of the child's kind
+	New func() O
+	// Served reports whether the cluster serves the child's kind, for kinds
+	// whose API is optional. Children of a kind that is not served are left
+	// alone: neither applied nor pruned, and kept in the inventory until the
+	// kind is served again. Nil means always served.
+	Served func(state T) bool
+	// Enabled reports whether the parent wants children of this kind at all,
+	// such as a feature its spec turns off. The existing children of a
+	// disabled kind are pruned. Nil means always enabled.
+	Enabled func(state T) bool
+	// Desired builds the objects that should exist. Objects of this kind
+	// owned by the parent that Desired no longer returns are pruned.
+	Desired func(ctx context.Context, state T) ([]O, error)
+	// Mutate copies the fields the controller manages from a desired object
+	// onto the existing one. Nil means objects are never updated once created.
+	Mutate func(existing O, desired O)
+	// Applied, if set, is called with every object as last returned by the
+	// API server, including its status
+	Applied func(state T, obj O)
+	// Ready, if set, reports whether the applied objects are ready and why.
+	// Nil means they are ready as soon as they are applied.
+	Ready func(objs []O) (bool, string)
+}
+
+// Reconciler is a Child of any object type, so children of different kinds
+// can be declared together in a Set.
+type Reconciler[T any] interface {
+	kind() string
+	newObject() client.Object
+	served(state T) bool
+	enabled(state T) bool
+	build(ctx context.Context, state T) ([]client.Object, error)
+	apply(
+		ctx context.Context, cli client.Client, owner client.Object, state T, objs []client.Object, opts applyOptions,
+	) (metav1.Condition, []Change, error)
+}
+
+// applyOptions are the settings of a Set that its children are applied with.
+type applyOptions struct {
+	adoptAnnotation string
+	apiReader       client.Reader
+}
+
+// Result is the outcome of reconciling the children of a parent.
+type Result struct {
+	// Conditions has one condition for each kind of child that was reconciled
+	Conditions []metav1.Condition
+	// Changes lists the changes made to children, or the changes that would
+	// have been made when reconciling with a dry-run client
+	Changes []Change
+}
+
+// Action is what a Change does to a child.
+type Action string
+
+const (
+	ActionCreate Action = "Create"
+	ActionUpdate Action = "Update"
+	ActionDelete Action = "Delete"
+)
+
+// Change is a change made to a child.
+type Change struct {
+	Action Action
+	Kind   string
+	Name   string
+	// Diff is the JSON merge patch of an update
+	Diff string
+}
+
+// ConflictError is returned when a child already exists but is not
+// controlled by its parent, such as an object created by someone else or the
+// child of another parent that was given the same name. Such objects are
+// left untouched unless annotated for adoption.
+type ConflictError struct {
+	Kind string
+	Name string
+	// Controller is the controller of the existing object, if it has one
+	Controller *metav1.OwnerReference
+}
+
+func (e *ConflictError) Error() string {
+	if e.Controller == nil {
+		return fmt.Sprintf("%s %s already exists and is not controlled by its parent", e.Kind, e.Name)
+	}
+	return fmt.Sprintf("%s %s already exists and is controlled by %s %s", e.Kind, e.Name, e.Controller.Kind, e.Controller.Name)
+}
+
+// CheckController returns a ConflictError unless obj is controlled by owner,
+// or may be adopted by it: it has no controller and its adoptAnnotation is set
+// to the owner's name. An empty adoptAnnotation disables adoption.
+func CheckController(kind string, obj client.Object, owner client.Object, adoptAnnotation string) error {
+	if metav1.IsControlledBy(obj, owner) {
+		return nil
+	}
+	controller := metav1.GetControllerOf(obj)
+	if controller == nil && adoptAnnotation != "" && obj.GetAnnotations()[adoptAnnotation] == owner.GetName() {
+		return nil
+	}
+
+	return &ConflictError{Kind: kind, Name: obj.GetName(), Controller: controller}
+}
+
+// Set is the list of children of a kind of parent.
+type Set[T any] struct {
+	// InventoryLabel is set on every child to the name of its parent, or to
+	// a truncated and hashed name if it is too long for a label value
+	InventoryLabel string
+	// InventoryAnnotation records on the parent every child created for it,
+	// so that children are pruned even if their kind is no longer reconciled
+	// or the controller restarted after creating them
+	InventoryAnnotation string
+	// AdoptAnnotation, set to the name of the parent on an existing object
+	// that no other object controls, lets the parent adopt it as a child.
+	// Without it, existing objects are never adopted.
+	AdoptAnnotation string
+	// Labels are set on every child, such as the label a cache restricted to
+	// the parent's children selects them by
+	Labels map[string]string
+	// APIReader, if set, reads children that already exist but are missing
+	// from the client's cache, such as objects created before they carried
+	// the Labels the cache selects. They are labelled once read.
+	APIReader client.Reader
+	// Children are applied in order and pruned in reverse order, so that
+	// objects which depend on others are created after and removed before them
+	Children []Reconciler[T]
+}
+
+// WithAPIReader returns a copy of the set that reads the children missing
+// from the client's cache with reader.
+func (s *Set[T]) WithAPIReader(reader client.Reader) *Set[T] {
+	c := *s
+	c.APIReader = reader
+	return &c
+}
+
+// Reconcile applies every child of the parent, then prunes the children that
+// are no longer desired. All children are built before any is applied, and
+// recorded in the parent's inventory before they are created. It reports the
+// conditions of the children it got to, and stops at the first child that
+// fails to build or apply.
+//
+// Reconciling with a client created by client.NewDryRunClient changes
+// nothing, and reports the changes that would have been made.
+func (s *Set[T]) Reconcile(ctx context.Context, cli client.Client, owner client.Object, state T) (Result, error) {
+	result := Result{Conditions: make([]metav1.Condition, 0, len(s.Children))}
+	fail := func(c Reconciler[T], reason string, err error) (Result, error) {
+		result.Conditions = append(result.Conditions, metav1.Condition{
+			Type:               ConditionType(c.kind()),
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            err.Error(),
+			ObservedGeneration: owner.GetGeneration(),
+		})
+		return result, err
+	}
+
+	recorded, err := s.inventory(owner)
+	if err != nil {
+		return result, err
+	}
+
+	// Entries of kinds that are not served stay in the inventory untouched,
+	// since their API may be served again. Disabled kinds have no desired
+	// children, so theirs are pruned
+	desired := make([][]client.Object, len(s.Children))
+	keep := sets.New[InventoryEntry]()
+	for i, c := range s.Children {
+		gvk, err := apiutil.GVKForObject(c.newObject(), cli.Scheme())
+		if !c.served(state) {
+			for e := range recorded {
+				if err == nil && e.gvk() == gvk {
+					keep.Insert(e)
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		if !c.enabled(state) {
+			continue
+		}
+
+		desired[i], err = c.build(ctx, state)
+		if err != nil {
+			return fail(c, "BuildFailed", fmt.Errorf("failed to build %s: %w", c.kind(), err))
+		}
+		for _, obj := range desired[i] {
+			keep.Insert(newEntry(gvk, obj.GetName()))
+		}
+	}
+
+	err = s.record(ctx, cli, owner, recorded.Union(keep))
+	if err != nil {
+		return result, fmt.Errorf("failed to record inventory: %w", err)
+	}
+
+	for i, c := range s.Children {
+		if !c.served(state) || !c.enabled(state) {
+			continue
+		}
+		for _, obj := range desired[i] {
+			err = s.prepare(obj, owner, cli.Scheme())
+			if err != nil {
+				return fail(c, "ApplyFailed", err)
+			}
+		}
+		applyCtx, span := tracing.Start(ctx, "children.apply", append(ownerAttributes(owner),
+			tracing.ChildKindKey.String(c.kind()))...)
+		condition, changes, err := c.apply(applyCtx, cli, owner, state, desired[i], s.applyOptions())
+		tracing.End(span, condition.Reason, err)
+		condition.ObservedGeneration = owner.GetGeneration()
+		result.Conditions = append(result.Conditions, condition)
+		result.Changes = append(result.Changes, changes...)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	pruneCtx, span := tracing.Start(ctx, "children.prune", ownerAttributes(owner)...)
+	pruned, err := s.prune(pruneCtx, cli, owner, state, recorded, keep)
+	tracing.End(span, pruneResult(pruned, err), err)
+	result.Changes = append(result.Changes, pruned...)
+	if err != nil {
+		return result, err
+	}
+
+	return result, s.record(ctx, cli, owner, keep)
+}
+
+// ownerAttributes returns the span attributes identifying a parent.
+func ownerAttributes(owner client.Object) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		tracing.NameKey.String(owner.GetName()),
+		tracing.NamespaceKey.String(owner.GetNamespace()),
+	}
+}
+
+// pruneResult returns the span result of pruning children.
+func pruneResult(pruned []Change, err error) string {
+	switch {
+	case err != nil:
+		return ""
+	case len(pruned) > 0:
+		return "Pruned"
+	default:
+		return "Unchanged"
+	}
+}
+
+// Render returns the children of a parent as Reconcile would apply them to a
+// cluster, in order, without talking to one. Children of kinds that are not
+// served or disabled are left out.
+func (s *Set[T]) Render(ctx context.Context, scheme *runtime.Scheme, owner client.Object, state T) ([]client.Object, error) {
+	var objs []client.Object
+	for _, c := range s.Children {
+		if !c.served(state) || !c.enabled(state) {
+			continue
+		}
+		desired, err := c.build(ctx, state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s: %w", c.kind(), err)
+		}
+		for _, obj := range desired {
+			err = s.prepare(obj, owner, scheme)
+			if err != nil {
+				return nil, err
+			}
+		}
+		objs = append(objs, desired...)
+	}
+
+	return objs, nil
+}
+
+// applyOptions returns the options children are applied with.
+func (s *Set[T]) applyOptions() applyOptions {
+	return applyOptions{adoptAnnotation: s.AdoptAnnotation, apiReader: s.APIReader}
+}
+
+// prepare labels a desired child with its parent and makes the parent its controller.
+func (s *Set[T]) prepare(obj client.Object, owner client.Object, scheme *runtime.Scheme) error {
+	for k, v := range s.Labels {
+		setLabel(obj, k, v)
+	}
+	setLabel(obj, s.InventoryLabel, names.LabelValue(owner.GetName()))
+	return controllerutil.SetControllerReference(owner, obj, scheme)
+}
+
+// prune deletes the children that are recorded in the inventory, or carry
+// the inventory label, but are no longer desired, including every child of
+// a disabled kind. Children of unknown kinds are pruned first, then the known
+// ones in reverse order. Kinds that are not served are left alone.
+func (s *Set[T]) prune(
+	ctx context.Context, cli client.Client, owner client.Object, state T, recorded sets.Set[InventoryEntry], keep sets.Set[InventoryEntry],
+) ([]Change, error) {
+	orphans := recorded.Difference(keep)
+	order := map[schema.GroupVersionKind]int{}
+	for i, c := range s.Children {
+		if !c.served(state) {
+			continue
+		}
+		gvk, err := apiutil.GVKForObject(c.newObject(), cli.Scheme())
+		if err != nil {
+			return nil, err
+		}
+		order[gvk] = i + 1
+
+		// Children created before they were recorded in the inventory are
+		// found by their label
+		labelled, err := listLabelled(ctx, cli, owner, gvk, s.InventoryLabel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", c.kind(), err)
+		}
+		orphans.Insert(labelled.Difference(keep).UnsortedList()...)
+	}
+
+	sorted := orphans.UnsortedList()
+	slices.SortFunc(sorted, func(a, b InventoryEntry) int {
+		return cmp.Or(order[b.gvk()]-order[a.gvk()], a.compare(b))
+	})
+	var changes []Change
+	for _, e := range sorted {
+		deleted, err := deleteChild(ctx, cli, owner, e)
+		if err != nil {
+			return changes, fmt.Errorf("failed to prune %s %s: %w", e.Kind, e.Name, err)
+		}
+		if deleted {
+			changes = append(changes, Change{Action: ActionDelete, Kind: e.Kind, Name: e.Name})
+		}
+	}
+
+	return changes, nil
+}
+
+// inventory returns the children recorded on the parent.
+func (s *Set[T]) inventory(owner client.Object) (sets.Set[InventoryEntry], error) {
+	recorded := sets.New[InventoryEntry]()
+	value, ok := owner.GetAnnotations()[s.InventoryAnnotation]
+	if !ok {
+		return recorded, nil
+	}
+
+	var entries []InventoryEntry
+	err := json.Unmarshal([]byte(value), &entries)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", s.InventoryAnnotation, err)
+	}
+
+	return recorded.Insert(entries...), nil
+}
+
+// record stores the inventory on the parent if it changed.
+func (s *Set[T]) record(ctx context.Context, cli client.Client, owner client.Object, inventory sets.Set[InventoryEntry]) error {
+	entries := inventory.UnsortedList()
+	slices.SortFunc(entries, InventoryEntry.compare)
+	value, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	current, ok := owner.GetAnnotations()[s.InventoryAnnotation]
+	if current == string(value) || (!ok && len(entries) == 0) {
+		return nil
+	}
+
+	before := owner.DeepCopyObject().(client.Object)
+	annotations := owner.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[s.InventoryAnnotation] = string(value)
+	owner.SetAnnotations(annotations)
+
+	return cli.Patch(ctx, owner, client.MergeFrom(before))
+}
+
+// ConditionType returns the type of the status condition reported for a kind of child.
+func ConditionType(kind string) string {
+	return kind + "Ready"
+}
+
+func (c *Child[T, O]) kind() string {
+	return c.Kind
+}
+
+func (c *Child[T, O]) served(state T) bool {
+	return c.Served == nil || c.Served(state)
+}
+
+func (c *Child[T, O]) enabled(state T) bool {
+	return c.Enabled == nil || c.Enabled(state)
+}
+
+func (c *Child[T, O]) newObject() client.Object {
+	return c.New()
+}
+
+func (c *Child[T, O]) build(ctx context.Context, state T) ([]client.Object, error) {
+	desired, err := c.Desired(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make([]client.Object, 0, len(desired))
+	for _, obj := range desired {
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+func (c *Child[T, O]) apply(
+	ctx context.Context, cli client.Client, owner client.Object, state T, desired []client.Object, opts applyOptions,
+) (metav1.Condition, []Change, error) {
+	condition := metav1.Condition{
+		Type:   ConditionType(c.Kind),
+		Status: metav1.ConditionFalse,
+	}
+
+	applied := make([]O, 0, len(desired))
+	var changes []Change
+	for _, d := range desired {
+		obj, change, err := c.applyOne(ctx, cli, owner, d.(O), opts)
+		if err != nil {
+			condition.Reason = "ApplyFailed"
+			if errors.As(err, new(*ConflictError)) {
+				condition.Reason = "Conflict"
+			}
+			condition.Message = err.Error()
+			return condition, changes, fmt.Errorf("failed to apply %s %s: %w", c.Kind, d.GetName(), err)
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+		applied = append(applied, obj)
+		if c.Applied != nil {
+			c.Applied(state, obj)
+		}
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "Applied"
+	condition.Message = fmt.Sprintf("%d %s object(s) applied", len(applied), c.Kind)
+	if c.Ready != nil {
+		if ready, message := c.Ready(applied); !ready {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "NotReady"
+			condition.Message = message
+		}
+	}
+
+	return condition, changes, nil
+}
+
+// applyOne creates the desired object, or patches the managed fields of the
+// existing one when they differ. It returns the change it made, if any, and a
+// ConflictError if the existing object is neither controlled by the owner nor
+// annotated for adoption.
+func (c *Child[T, O]) applyOne(
+	ctx context.Context, cli client.Client, owner client.Object, desired O, opts applyOptions,
+) (O, *Change, error) {
+	existing := c.New()
+	err := cli.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if k8serr.IsNotFound(err) {
+		change := &Change{Action: ActionCreate, Kind: c.Kind, Name: desired.GetName()}
+		err = cli.Create(ctx, desired)
+		if !k8serr.IsAlreadyExists(err) || opts.apiReader == nil {
+			return desired, change, err
+		}
+		// The object exists but is not cached, so read it from the API server
+		err = opts.apiReader.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	}
+	if err != nil {
+		return desired, nil, err
+	}
+	err = CheckController(c.Kind, existing, owner, opts.adoptAnnotation)
+	if err != nil {
+		return desired, nil, err
+	}
+
+	before := existing.DeepCopyObject().(O)
+	for k, v := range desired.GetLabels() {
+		setLabel(existing, k, v)
+	}
+	err = controllerutil.SetControllerReference(owner, existing, cli.Scheme())
+	if err != nil {
+		return desired, nil, err
+	}
+	if c.Mutate != nil {
+		c.Mutate(existing, desired)
+	}
+	if equality.Semantic.DeepEqual(before, existing) {
+		return existing, nil, nil
+	}
+
+	patch := client.MergeFrom(before)
+	diff, err := patch.Data(existing)
+	if err != nil {
+		return desired, nil, err
+	}
+	change := &Change{Action: ActionUpdate, Kind: c.Kind, Name: desired.GetName(), Diff: string(diff)}
+
+	return existing, change, cli.Patch(ctx, existing, patch)
+}
+
+// listLabelled returns the objects of a kind that carry the inventory label
+// of the owner and are controlled by it.
+func listLabelled(
+	ctx context.Context, cli client.Client, owner client.Object, gvk schema.GroupVersionKind, inventoryLabel string,
+) (sets.Set[InventoryEntry], error) {
+	list := emptyList(cli.Scheme(), gvk)
+	err := cli.List(ctx, list, client.InNamespace(owner.GetNamespace()), client.MatchingLabels{
+		inventoryLabel: names.LabelValue(owner.GetName()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	labelled := sets.New[InventoryEntry]()
+	err = meta.EachListItem(list, func(item runtime.Object) error {
+		obj, ok := item.(client.Object)
+		if ok && metav1.IsControlledBy(obj, owner) {
+			labelled.Insert(newEntry(gvk, obj.GetName()))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return labelled, nil
+}
+
+// deleteChild deletes a child unless it is gone or no longer controlled by
+// the owner, such as an object recreated by someone else under the same name.
+// It reports whether the child was deleted.
+func deleteChild(ctx context.Context, cli client.Client, owner client.Object, e InventoryEntry) (bool, error) {
+	obj := emptyObject(cli.Scheme(), e.gvk())
+	err := cli.Get(ctx, client.ObjectKey{Name: e.Name, Namespace: owner.GetNamespace()}, obj)
+	if k8serr.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !metav1.IsControlledBy(obj, owner) {
+		return false, nil
+	}
+
+	err = cli.Delete(ctx, obj)
+	if k8serr.IsNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// emptyObject returns an empty object of a kind. Kinds in the scheme are typed,
+// so that they are read from the informer the parent's controller already
+// holds for the kind instead of a second, metadata-only one. Other kinds,
+// such as those of children no longer declared, are unstructured, which the
+// manager's client reads from the API server.
+func emptyObject(scheme *runtime.Scheme, gvk schema.GroupVersionKind) client.Object {
+	if obj, err := scheme.New(gvk); err == nil {
+		if obj, ok := obj.(client.Object); ok {
+			return obj
+		}
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// emptyList returns an empty list of a kind, typed like emptyObject.
+func emptyList(scheme *runtime.Scheme, gvk schema.GroupVersionKind) client.ObjectList {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	if list, err := scheme.New(listGVK); err == nil {
+		if list, ok := list.(client.ObjectList); ok {
+			return list
+		}
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(listGVK)
+	return list
+}
+
+func setLabel(obj client.Object, key string, value string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[key] = value
+	obj.SetLabels(labels)
+}
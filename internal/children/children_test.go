@@ -0,0 +1,487 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package children
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/opendatahub-io/sample-component/internal/tracing"
+)
+
+const (
+	inventoryLabel      = "example.com/owner"
+	inventoryAnnotation = "example.com/inventory"
+	adoptAnnotation     = "example.com/adopt"
+)
+
+// state is the parent state of the children under test: the names of the
+// ConfigMaps to create and the value they hold.
+type state struct {
+	names    []string
+	value    string
+	enabled  bool
+	unserved bool
+}
+
+func configMaps() *Child[*state, *corev1.ConfigMap] {
+	return &Child[*state, *corev1.ConfigMap]{
+		Kind:    "ConfigMap",
+		New:     func() *corev1.ConfigMap { return &corev1.ConfigMap{} },
+		Served:  func(s *state) bool { return !s.unserved },
+		Enabled: func(s *state) bool { return s.enabled },
+		Desired: func(_ context.Context, s *state) ([]*corev1.ConfigMap, error) {
+			var cms []*corev1.ConfigMap
+			for _, name := range s.names {
+				cms = append(cms, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Data:       map[string]string{"value": s.value},
+				})
+			}
+			return cms, nil
+		},
+		Mutate: func(existing *corev1.ConfigMap, desired *corev1.ConfigMap) {
+			existing.Data["value"] = desired.Data["value"]
+		},
+	}
+}
+
+var _ = Describe("Children", func() {
+	ctx := context.Background()
+
+	var cli client.Client
+	var owner *corev1.Secret
+	var set *Set[*state]
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		owner = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "parent-uid", Generation: 3},
+		}
+		cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(owner).Build()
+		set = &Set[*state]{
+			InventoryLabel:      inventoryLabel,
+			InventoryAnnotation: inventoryAnnotation,
+			AdoptAnnotation:     adoptAnnotation,
+			Children:            []Reconciler[*state]{configMaps()},
+		}
+	})
+
+	It("creates the desired children with an owner label and controller reference", func() {
+		result, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a", "b"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Conditions).To(HaveLen(1))
+		Expect(result.Conditions[0].Type).To(Equal("ConfigMapReady"))
+		Expect(result.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+		Expect(result.Conditions[0].ObservedGeneration).To(Equal(int64(3)))
+
+		for _, name := range []string{"a", "b"} {
+			cm := &corev1.ConfigMap{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, cm)).To(Succeed())
+			Expect(cm.Labels).To(HaveKeyWithValue(inventoryLabel, "parent"))
+			Expect(metav1.IsControlledBy(cm, owner)).To(BeTrue())
+			Expect(cm.Data).To(HaveKeyWithValue("value", "1"))
+		}
+	})
+
+	It("patches existing children only when their managed fields differ", func() {
+		s := &state{names: []string{"a"}, value: "1", enabled: true}
+		_, err := set.Reconcile(ctx, cli, owner, s)
+		Expect(err).NotTo(HaveOccurred())
+		cm := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).To(Succeed())
+		resourceVersion := cm.ResourceVersion
+
+		_, err = set.Reconcile(ctx, cli, owner, s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).To(Succeed())
+		Expect(cm.ResourceVersion).To(Equal(resourceVersion))
+
+		s.value = "2"
+		_, err = set.Reconcile(ctx, cli, owner, s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).To(Succeed())
+		Expect(cm.ResourceVersion).NotTo(Equal(resourceVersion))
+		Expect(cm.Data).To(HaveKeyWithValue("value", "2"))
+	})
+
+	It("prunes children that are no longer desired", func() {
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a", "b"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Labelled like a child but not controlled by the parent
+		foreign := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "default", Labels: map[string]string{inventoryLabel: "parent"}},
+		}
+		Expect(cli.Create(ctx, foreign)).To(Succeed())
+
+		_, err = set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := &corev1.ConfigMapList{}
+		Expect(cli.List(ctx, cms, client.InNamespace("default"))).To(Succeed())
+		names := []string{}
+		for _, cm := range cms.Items {
+			names = append(names, cm.Name)
+		}
+		Expect(names).To(ConsistOf("a", "c"))
+	})
+
+	It("neither applies nor prunes children of kinds that are not served", func() {
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := set.Reconcile(ctx, cli, owner, &state{enabled: true, unserved: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Conditions).To(BeEmpty())
+		Expect(result.Changes).To(BeEmpty())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	It("prunes the children of a disabled kind", func() {
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a", "b"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a", "b"}, value: "1", enabled: false})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Conditions).To(BeEmpty())
+		Expect(result.Changes).To(ConsistOf(
+			Change{Action: ActionDelete, Kind: "ConfigMap", Name: "a"},
+			Change{Action: ActionDelete, Kind: "ConfigMap", Name: "b"},
+		))
+		cms := &corev1.ConfigMapList{}
+		Expect(cli.List(ctx, cms, client.InNamespace("default"))).To(Succeed())
+		Expect(cms.Items).To(BeEmpty())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(owner), owner)).To(Succeed())
+		Expect(owner.Annotations[inventoryAnnotation]).To(Equal("[]"))
+	})
+
+	It("refuses existing objects it does not control", func() {
+		Expect(cli.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"},
+			Data:       map[string]string{"value": "0"},
+		})).To(Succeed())
+
+		result, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		var conflict *ConflictError
+		Expect(errors.As(err, &conflict)).To(BeTrue())
+		Expect(conflict.Name).To(Equal("a"))
+		Expect(conflict.Controller).To(BeNil())
+		Expect(result.Conditions).To(HaveLen(1))
+		Expect(result.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+		Expect(result.Conditions[0].Reason).To(Equal("Conflict"))
+
+		cm := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).To(Succeed())
+		Expect(cm.Labels).NotTo(HaveKey(inventoryLabel))
+		Expect(cm.OwnerReferences).To(BeEmpty())
+		Expect(cm.Data).To(HaveKeyWithValue("value", "0"))
+	})
+
+	It("refuses the children of another parent", func() {
+		other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"}}
+		taken := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}}
+		Expect(controllerutil.SetControllerReference(other, taken, cli.Scheme())).To(Succeed())
+		Expect(cli.Create(ctx, taken)).To(Succeed())
+
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).To(MatchError(ContainSubstring("ConfigMap a already exists and is controlled by Secret other")))
+	})
+
+	It("adopts existing objects annotated for adoption by the parent", func() {
+		Expect(cli.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "a",
+				Namespace:   "default",
+				Annotations: map[string]string{adoptAnnotation: "parent"},
+			},
+			Data: map[string]string{"value": "0"},
+		})).To(Succeed())
+
+		result, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Changes).To(ConsistOf(HaveField("Action", ActionUpdate)))
+
+		cm := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).To(Succeed())
+		Expect(cm.Labels).To(HaveKeyWithValue(inventoryLabel, "parent"))
+		Expect(metav1.IsControlledBy(cm, owner)).To(BeTrue())
+		Expect(cm.Data).To(HaveKeyWithValue("value", "1"))
+	})
+
+	It("does not adopt objects annotated for another parent or controlled by one", func() {
+		other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"}}
+		b := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:        "b",
+			Namespace:   "default",
+			Annotations: map[string]string{adoptAnnotation: "parent"},
+		}}
+		Expect(controllerutil.SetControllerReference(other, b, cli.Scheme())).To(Succeed())
+		Expect(cli.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:        "a",
+			Namespace:   "default",
+			Annotations: map[string]string{adoptAnnotation: "other"},
+		}})).To(Succeed())
+		Expect(cli.Create(ctx, b)).To(Succeed())
+
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).To(MatchError(ContainSubstring("ConfigMap a already exists and is not controlled by its parent")))
+		_, err = set.Reconcile(ctx, cli, owner, &state{names: []string{"b"}, value: "1", enabled: true})
+		Expect(err).To(MatchError(ContainSubstring("ConfigMap b already exists and is controlled by Secret other")))
+	})
+
+	It("sets the labels of the set on every child", func() {
+		set.Labels = map[string]string{"example.com/managed-by": "test"}
+
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).To(Succeed())
+		Expect(cm.Labels).To(HaveKeyWithValue("example.com/managed-by", "test"))
+	})
+
+	It("reads children missing from the cache with the API reader", func() {
+		set.Labels = map[string]string{"example.com/managed-by": "test"}
+		existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}, Data: map[string]string{"value": "0"}}
+		Expect(controllerutil.SetControllerReference(owner, existing, cli.Scheme())).To(Succeed())
+		Expect(cli.Create(ctx, existing)).To(Succeed())
+
+		// The cached client only sees objects with the labels of the set
+		cached := interceptor.NewClient(cli.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				err := c.Get(ctx, key, obj, opts...)
+				if err == nil && obj.GetLabels()["example.com/managed-by"] != "test" {
+					return k8serr.NewNotFound(corev1.Resource("configmaps"), key.Name)
+				}
+				return err
+			},
+		})
+
+		_, err := set.Reconcile(ctx, cached, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).To(MatchError(ContainSubstring("already exists")))
+
+		result, err := set.WithAPIReader(cli).Reconcile(ctx, cached, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Changes).To(ConsistOf(HaveField("Action", ActionUpdate)))
+
+		cm := &corev1.ConfigMap{}
+		Expect(cached.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("value", "1"))
+	})
+
+	It("labels children of parents with long names with a valid label value", func() {
+		owner = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("parent", 20), Namespace: "default", UID: "long-uid"},
+		}
+		Expect(cli.Create(ctx, owner)).To(Succeed())
+
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).To(Succeed())
+		Expect(validation.IsValidLabelValue(cm.Labels[inventoryLabel])).To(BeEmpty())
+
+		_, err = set.Reconcile(ctx, cli, owner, &state{enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, cm)).NotTo(Succeed())
+	})
+
+	It("reports a failing child in its condition and stops", func() {
+		failing := configMaps()
+		failing.Kind = "Failing"
+		failing.Desired = func(context.Context, *state) ([]*corev1.ConfigMap, error) {
+			return nil, errors.New("boom")
+		}
+		set.Children = []Reconciler[*state]{failing, configMaps()}
+
+		result, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).To(MatchError(ContainSubstring("boom")))
+		Expect(result.Conditions).To(HaveLen(1))
+		Expect(result.Conditions[0].Type).To(Equal("FailingReady"))
+		Expect(result.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+		Expect(result.Conditions[0].Reason).To(Equal("BuildFailed"))
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, &corev1.ConfigMap{})).NotTo(Succeed())
+	})
+
+	It("records the children in the inventory of the parent", func() {
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"b", "a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		parent := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(owner), parent)).To(Succeed())
+		Expect(parent.Annotations).To(HaveKeyWithValue(inventoryAnnotation,
+			`[{"apiVersion":"v1","kind":"ConfigMap","name":"a"},{"apiVersion":"v1","kind":"ConfigMap","name":"b"}]`))
+
+		_, err = set.Reconcile(ctx, cli, owner, &state{names: []string{"b"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(owner), parent)).To(Succeed())
+		Expect(parent.Annotations).To(HaveKeyWithValue(inventoryAnnotation,
+			`[{"apiVersion":"v1","kind":"ConfigMap","name":"b"}]`))
+	})
+
+	It("prunes recorded children of kinds that are no longer reconciled", func() {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		}
+		Expect(controllerutil.SetControllerReference(owner, service, cli.Scheme())).To(Succeed())
+		Expect(cli.Create(ctx, service)).To(Succeed())
+		// Recorded by a previous controller, but not controlled by the parent anymore
+		Expect(cli.Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "taken", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		})).To(Succeed())
+		owner.Annotations = map[string]string{inventoryAnnotation: `[` +
+			`{"apiVersion":"v1","kind":"Service","name":"svc"},` +
+			`{"apiVersion":"v1","kind":"Service","name":"taken"},` +
+			`{"apiVersion":"v1","kind":"Service","name":"gone"}]`}
+		Expect(cli.Update(ctx, owner)).To(Succeed())
+
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8serr.IsNotFound(cli.Get(ctx, client.ObjectKeyFromObject(service), &corev1.Service{}))).To(BeTrue())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "taken", Namespace: "default"}, &corev1.Service{})).To(Succeed())
+
+		parent := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(owner), parent)).To(Succeed())
+		Expect(parent.Annotations).To(HaveKeyWithValue(inventoryAnnotation,
+			`[{"apiVersion":"v1","kind":"ConfigMap","name":"a"}]`))
+	})
+
+	It("keeps the inventory of children of kinds that are not served", func() {
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = set.Reconcile(ctx, cli, owner, &state{enabled: true, unserved: true})
+		Expect(err).NotTo(HaveOccurred())
+		parent := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(owner), parent)).To(Succeed())
+		Expect(parent.Annotations).To(HaveKeyWithValue(inventoryAnnotation,
+			`[{"apiVersion":"v1","kind":"ConfigMap","name":"a"}]`))
+	})
+
+	It("reports the changes it makes", func() {
+		result, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a", "b"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Changes).To(ConsistOf(
+			Change{Action: ActionCreate, Kind: "ConfigMap", Name: "a"},
+			Change{Action: ActionCreate, Kind: "ConfigMap", Name: "b"},
+		))
+
+		result, err = set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "2", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Changes).To(ConsistOf(
+			Change{Action: ActionUpdate, Kind: "ConfigMap", Name: "a", Diff: `{"data":{"value":"2"}}`},
+			Change{Action: ActionDelete, Kind: "ConfigMap", Name: "b"},
+		))
+	})
+
+	It("changes nothing with a dry-run client", func() {
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a", "b"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := set.Reconcile(ctx, client.NewDryRunClient(cli), owner,
+			&state{names: []string{"a", "c"}, value: "2", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Changes).To(ConsistOf(
+			Change{Action: ActionUpdate, Kind: "ConfigMap", Name: "a", Diff: `{"data":{"value":"2"}}`},
+			Change{Action: ActionCreate, Kind: "ConfigMap", Name: "c"},
+			Change{Action: ActionDelete, Kind: "ConfigMap", Name: "b"},
+		))
+
+		cms := &corev1.ConfigMapList{}
+		Expect(cli.List(ctx, cms, client.InNamespace("default"))).To(Succeed())
+		Expect(cms.Items).To(HaveLen(2))
+		for _, cm := range cms.Items {
+			Expect(cm.Data).To(HaveKeyWithValue("value", "1"))
+		}
+		parent := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(owner), parent)).To(Succeed())
+		Expect(parent.Annotations[inventoryAnnotation]).NotTo(ContainSubstring(`"name":"c"`))
+	})
+
+	It("renders enabled children without a cluster", func() {
+		objs, err := set.Render(ctx, cli.Scheme(), owner, &state{names: []string{"a", "b"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(2))
+		for _, obj := range objs {
+			Expect(obj.GetLabels()).To(HaveKeyWithValue(inventoryLabel, "parent"))
+			Expect(metav1.IsControlledBy(obj, owner)).To(BeTrue())
+		}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "a", Namespace: "default"}, &corev1.ConfigMap{})).NotTo(Succeed())
+
+		objs, err = set.Render(ctx, cli.Scheme(), owner, &state{names: []string{"a"}, enabled: false})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(BeEmpty())
+	})
+
+	It("reports children that are not ready", func() {
+		child := configMaps()
+		child.Ready = func(cms []*corev1.ConfigMap) (bool, string) {
+			return false, "not yet"
+		}
+		set.Children = []Reconciler[*state]{child}
+
+		result, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+		Expect(result.Conditions[0].Reason).To(Equal("NotReady"))
+		Expect(result.Conditions[0].Message).To(Equal("not yet"))
+	})
+
+	It("traces each kind of child it applies and the pruning", func() {
+		exporter := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		ctx, parent := provider.Tracer(tracing.TracerName).Start(ctx, "parent")
+
+		_, err := set.Reconcile(ctx, cli, owner, &state{names: []string{"a"}, value: "1", enabled: true})
+		Expect(err).NotTo(HaveOccurred())
+		parent.End()
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(3))
+		Expect(spans[0].Name).To(Equal("children.apply"))
+		Expect(spans[0].Parent.SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(spans[0].Attributes).To(ContainElements(
+			tracing.NameKey.String("parent"),
+			tracing.NamespaceKey.String("default"),
+			tracing.ChildKindKey.String("ConfigMap"),
+			tracing.ResultKey.String("Applied"),
+		))
+		Expect(spans[1].Name).To(Equal("children.prune"))
+		Expect(spans[1].Attributes).To(ContainElement(tracing.ResultKey.String("Unchanged")))
+	})
+})
@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package children
+
+import (
+	"cmp"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// InventoryEntry identifies a child in the inventory of its parent, which is always in
+// the parent's namespace.
+type InventoryEntry struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+func newEntry(gvk schema.GroupVersionKind, name string) InventoryEntry {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return InventoryEntry{APIVersion: apiVersion, Kind: kind, Name: name}
+}
+
+func (e InventoryEntry) gvk() schema.GroupVersionKind {
+	return schema.FromAPIVersionAndKind(e.APIVersion, e.Kind)
+}
+
+// compare orders entries by API version, kind and name, which keeps the
+// recorded inventory stable.
+func (e InventoryEntry) compare(other InventoryEntry) int {
+	return cmp.Or(
+		cmp.Compare(e.APIVersion, other.APIVersion),
+		cmp.Compare(e.Kind, other.Kind),
+		cmp.Compare(e.Name, other.Name),
+	)
+}
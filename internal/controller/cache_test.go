@@ -0,0 +1,234 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gmeasure"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("HelloWorld cache", func() {
+	It("should hold a single informer for each kind of child", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		By("recording the representations the cache watches each resource in")
+		var mu sync.Mutex
+		watches := map[string]sets.Set[string]{}
+		watchCfg := rest.CopyConfig(cfg)
+		watchCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Query().Get("watch") == "true" {
+					mu.Lock()
+					if watches[req.URL.Path] == nil {
+						watches[req.URL.Path] = sets.New[string]()
+					}
+					watches[req.URL.Path].Insert(req.Header.Get("Accept"))
+					mu.Unlock()
+				}
+				return rt.RoundTrip(req)
+			})
+		})
+		opts := CacheOptions()
+		opts.Scheme = scheme.Scheme
+		c, err := cache.New(watchCfg, opts)
+		Expect(err).NotTo(HaveOccurred())
+		cli, err := client.New(watchCfg, client.Options{Scheme: scheme.Scheme, Cache: &client.CacheOptions{Reader: c}})
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			Expect(c.Start(ctx)).To(Succeed())
+		}()
+		Expect(c.WaitForCacheSync(ctx)).To(BeTrue())
+
+		By("watching the HelloWorlds and Deployments the way the controller does")
+		_, err = c.GetInformer(ctx, &helloworldv1.HelloWorld{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.GetInformer(ctx, &appsv1.Deployment{})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("reconciling a HelloWorld and pruning its Route through the cache")
+		registry := startRegistry()
+		reconciler := newReconciler(registry)
+		reconciler.Client = cli
+		reconciler.APIReader = k8sClient
+		reconciler.EnableRoutes = true
+		hw := &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cached-resource",
+				Namespace: "default",
+			},
+			Spec: helloworldv1.HelloWorldSpec{
+				Image:    nginxImage(registry),
+				Exposure: helloworldv1.RouteExposureType,
+			},
+		}
+		Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(context.Background(), hw)).To(Succeed())
+		})
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)}
+		stableName := types.NamespacedName{
+			Name:      helloWorldTrackName(hw, helloWorldStableTrack),
+			Namespace: "default",
+		}
+		Eventually(func(g Gomega) {
+			_, err := reconciler.Reconcile(ctx, req)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(k8sClient.Get(ctx, stableName, &routev1.Route{})).To(Succeed())
+		}).Should(Succeed())
+
+		Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+		hw.Spec.Exposure = helloworldv1.ServiceExposureType
+		Expect(k8sClient.Update(ctx, hw)).To(Succeed())
+		Eventually(func(g Gomega) {
+			_, err := reconciler.Reconcile(ctx, req)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(errors.IsNotFound(k8sClient.Get(ctx, stableName, &routev1.Route{}))).To(BeTrue())
+		}).Should(Succeed())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(watches).To(HaveKey(ContainSubstring("/deployments")))
+		Expect(watches).To(HaveKey(ContainSubstring("/routes")))
+		for path, accepts := range watches {
+			Expect(accepts.UnsortedList()).To(HaveLen(1), path)
+		}
+	})
+
+	// The cache benchmark compares the heap used by a manager cache of the
+	// ConfigMaps in a cluster with the default cache options, with CacheOptions,
+	// and with CacheOptions and metadata-only ConfigMaps as the controller caches
+	// content revisions. It only runs when HELLOWORLD_CACHE_BENCHMARK_OBJECTS sets
+	// the number of ConfigMaps not managed by the controller to create:
+	//
+	//	HELLOWORLD_CACHE_BENCHMARK_OBJECTS=10000 make test-cache-benchmark
+	It("should only hold the objects managed by the controller", Label("benchmark"), func() {
+		objects := os.Getenv("HELLOWORLD_CACHE_BENCHMARK_OBJECTS")
+		if objects == "" {
+			Skip("HELLOWORLD_CACHE_BENCHMARK_OBJECTS is not set")
+		}
+		n, err := strconv.Atoi(objects)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := context.Background()
+		benchCfg := rest.CopyConfig(cfg)
+		benchCfg.QPS = -1
+		cli, err := client.New(benchCfg, client.Options{Scheme: scheme.Scheme})
+		Expect(err).NotTo(HaveOccurred())
+
+		By(fmt.Sprintf("creating %d unmanaged and %d managed ConfigMaps", n, n/100))
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "cache-benchmark-"}}
+		Expect(cli.Create(ctx, namespace)).To(Succeed())
+		page := strings.Repeat("x", 1024)
+		for i := range n + n/100 {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("cm-%d", i),
+					Namespace: namespace.Name,
+				},
+				Data: map[string]string{"index.html": page},
+			}
+			if i >= n {
+				cm.Labels = map[string]string{helloWorldManagedByLabelKey: helloWorldManagedByLabelVal}
+			}
+			Expect(cli.Create(ctx, cm)).To(Succeed())
+		}
+
+		configMap := &corev1.ConfigMap{}
+		metadata := &metav1.PartialObjectMetadata{}
+		metadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+
+		experiment := gmeasure.NewExperiment("Cached ConfigMaps")
+		AddReportEntry(experiment.Name, experiment)
+		for _, variant := range []struct {
+			name string
+			opts cache.Options
+			obj  client.Object
+		}{
+			{"default options", cache.Options{}, configMap},
+			{"CacheOptions", CacheOptions(), configMap},
+			{"CacheOptions, metadata only", CacheOptions(), metadata},
+		} {
+			heap := cachedHeap(benchCfg, variant.opts, variant.obj)
+			experiment.RecordValue(variant.name, float64(heap)/(1<<20), gmeasure.Units("MiB"), gmeasure.Precision(2))
+		}
+
+		all := experiment.GetStats("default options").FloatFor(gmeasure.StatMin)
+		managed := experiment.GetStats("CacheOptions").FloatFor(gmeasure.StatMin)
+		Expect(managed).To(BeNumerically("<", all))
+	})
+})
+
+// cachedHeap returns the growth of the heap once a cache created with opts
+// has synced the informer of obj.
+func cachedHeap(cfg *rest.Config, opts cache.Options, obj client.Object) int64 {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	opts.Scheme = scheme.Scheme
+	c, err := cache.New(cfg, opts)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = c.GetInformer(ctx, obj)
+	Expect(err).NotTo(HaveOccurred())
+	go func() {
+		defer GinkgoRecover()
+		Expect(c.Start(ctx)).To(Succeed())
+	}()
+	Expect(c.WaitForCacheSync(ctx)).To(BeTrue())
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(c)
+
+	return int64(after.HeapAlloc) - int64(before.HeapAlloc)
+}
+
+// roundTripperFunc is an http.RoundTripper implemented by a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// drainContext returns a context for a reconcile that is not cancelled as
+// soon as ctx is, when the manager stops, but only timeout later, so that a
+// reconcile in flight finishes writing the objects it manages rather than
+// leaving them half updated for the next leader. The returned cancel function
+// must be called once the reconcile returns, which also ends the wait for the
+// timeout.
+func drainContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	drainCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-drainCtx.Done():
+		case <-timer.C:
+			cancel(fmt.Errorf("reconcile not drained within %s: %w", timeout, context.Cause(ctx)))
+		}
+	})
+
+	return drainCtx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
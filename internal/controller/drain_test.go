@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Draining reconciles", func() {
+	It("should outlive the manager context until the timeout", func() {
+		ctx, stopManager := context.WithCancel(context.Background())
+		drainCtx, cancel := drainContext(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		stopManager()
+		Consistently(drainCtx.Done()).WithTimeout(50 * time.Millisecond).ShouldNot(BeClosed())
+		Eventually(drainCtx.Done()).Should(BeClosed())
+		Expect(context.Cause(drainCtx)).To(MatchError(ContainSubstring("reconcile not drained within 100ms")))
+		Expect(context.Cause(drainCtx)).To(MatchError(context.Canceled))
+	})
+
+	It("should stop waiting for the timeout once the reconcile returns", func() {
+		before := runtime.NumGoroutine()
+		ctx, stopManager := context.WithCancel(context.Background())
+		drainCtx, cancel := drainContext(ctx, time.Hour)
+
+		stopManager()
+		Eventually(runtime.NumGoroutine).Should(BeNumerically(">", before))
+		cancel()
+		Expect(drainCtx.Err()).To(MatchError(context.Canceled))
+		Eventually(runtime.NumGoroutine).Should(BeNumerically("<=", before))
+	})
+
+	It("should be cancelled once the reconcile returns", func() {
+		drainCtx, cancel := drainContext(context.Background(), time.Hour)
+		Expect(drainCtx.Err()).NotTo(HaveOccurred())
+
+		cancel()
+		Expect(drainCtx.Err()).To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,291 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/image"
+	"github.com/opendatahub-io/sample-component/internal/image/registrytest"
+)
+
+var _ = Describe("HelloWorld Controller failover", func() {
+	It("should hand over to a standby manager without writing the children again", func() {
+		ctx := context.Background()
+
+		registry := startRegistry()
+
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "failover-"}}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+
+		By("electing the first of two managers")
+		leader := startFailoverManager(namespace.Name, registry, nil)
+		Eventually(leader.mgr.Elected()).Should(BeClosed())
+		standby := startFailoverManager(namespace.Name, registry, nil)
+		DeferCleanup(standby.stop)
+		Consistently(standby.mgr.Elected()).WithTimeout(time.Second).ShouldNot(BeClosed())
+
+		By("reconciling a HelloWorld with the leader")
+		hw := &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "failover",
+				Namespace: namespace.Name,
+			},
+			Spec: helloworldv1.HelloWorldSpec{
+				Image: nginxImage(registry),
+			},
+		}
+		Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hw), hw)).To(Succeed())
+			g.Expect(hw.Status.ObservedGeneration).To(Equal(hw.Generation))
+		}).Should(Succeed())
+		Eventually(leader.childWrites.Load).Should(BeNumerically(">", 0))
+		Consistently(leader.childWrites.Load).WithTimeout(time.Second).Should(Equal(leader.childWrites.Load()))
+		versions := childResourceVersions(ctx, namespace.Name)
+		Expect(versions).NotTo(BeEmpty())
+
+		By("handing over to the standby manager once the leader stops")
+		leader.stop()
+		// The lease is released on shutdown, so the standby does not wait for
+		// it to expire
+		Eventually(standby.mgr.Elected()).WithTimeout(5 * time.Second).Should(BeClosed())
+		Eventually(standby.reads.Load).Should(BeNumerically(">", 0))
+		Consistently(standby.childWrites.Load).WithTimeout(2 * time.Second).Should(BeZero())
+		Expect(childResourceVersions(ctx, namespace.Name)).To(Equal(versions))
+	})
+
+	It("should not write with the standby manager until the leader has drained its reconcile", func() {
+		ctx := context.Background()
+
+		registry := startRegistry()
+
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "drain-"}}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+
+		By("electing the first of two managers, its writes held at a gate")
+		gate := make(chan struct{})
+		leader := startFailoverManager(namespace.Name, registry, gate)
+		Eventually(leader.mgr.Elected()).Should(BeClosed())
+		standby := startFailoverManager(namespace.Name, registry, nil)
+		DeferCleanup(standby.stop)
+
+		By("blocking the reconcile of a HelloWorld by the leader mid-write")
+		hw := &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "drain",
+				Namespace: namespace.Name,
+			},
+			Spec: helloworldv1.HelloWorldSpec{
+				Image: nginxImage(registry),
+			},
+		}
+		Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+		Eventually(leader.gated).WithTimeout(10 * time.Second).Should(BeClosed())
+
+		By("stopping the leader while its reconcile is blocked")
+		stopped := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(stopped)
+			leader.stop()
+		}()
+		Consistently(standby.mgr.Elected()).WithTimeout(time.Second).ShouldNot(BeClosed())
+		Expect(stopped).NotTo(BeClosed())
+		Expect(standby.childWrites.Load()).To(BeZero())
+
+		By("handing over to the standby manager once the reconcile is drained")
+		close(gate)
+		Eventually(stopped).WithTimeout(15 * time.Second).Should(BeClosed())
+		Expect(leader.childWrites.Load()).To(BeNumerically(">", 1))
+		Eventually(standby.mgr.Elected()).WithTimeout(5 * time.Second).Should(BeClosed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hw), hw)).To(Succeed())
+			g.Expect(hw.Status.ObservedGeneration).To(Equal(hw.Generation))
+		}).Should(Succeed())
+		if first := standby.firstChildWrite.Load(); first != nil {
+			Expect(first.After(leader.stoppedAt)).To(BeTrue(),
+				"the standby wrote a child at %s, before the leader stopped at %s", first, leader.stoppedAt)
+		}
+	})
+})
+
+// failoverManager is a manager reconciling HelloWorlds under leader election,
+// counting the HelloWorlds it reads and the writes it makes to their
+// children. If gate is set, its first write to a child in namespace closes
+// gated and blocks until gate is closed.
+type failoverManager struct {
+	mgr             ctrl.Manager
+	reads           atomic.Int32
+	childWrites     atomic.Int32
+	firstChildWrite atomic.Pointer[time.Time]
+	namespace       string
+	gate            chan struct{}
+	gated           chan struct{}
+	gatedOnce       sync.Once
+	stop            func()
+	stoppedAt       time.Time
+}
+
+// startFailoverManager starts a manager holding its leader election lease in
+// namespace, its first write to a child held at gate if it is not nil.
+// Stopping it waits for it to shut down.
+func startFailoverManager(namespace string, registry *registrytest.Registry, gate chan struct{}) *failoverManager {
+	GinkgoHelper()
+	m := &failoverManager{namespace: namespace, gate: gate, gated: make(chan struct{})}
+
+	var err error
+	m.mgr, err = ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		// Both managers run a controller named helloworld
+		Controller:                    config.Controller{SkipNameValidation: ptr.To(true)},
+		LeaderElection:                true,
+		LeaderElectionID:              "failover.helloworld.opendatahub.io",
+		LeaderElectionNamespace:       namespace,
+		LeaseDuration:                 ptr.To(15 * time.Second),
+		RenewDeadline:                 ptr.To(10 * time.Second),
+		RetryPeriod:                   ptr.To(200 * time.Millisecond),
+		LeaderElectionReleaseOnCancel: true,
+		GracefulShutdownTimeout:       ptr.To(10 * time.Second),
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect((&HelloWorldReconciler{
+		Client:       &countingClient{Client: m.mgr.GetClient(), m: m},
+		Scheme:       m.mgr.GetScheme(),
+		Recorder:     &record.FakeRecorder{},
+		Resolver:     &image.RegistryResolver{Client: registry.Client()},
+		Clock:        clock.RealClock{},
+		DrainTimeout: 5 * time.Second,
+	}).SetupWithManager(m.mgr)).To(Succeed())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- m.mgr.Start(ctx)
+	}()
+	var stopped bool
+	m.stop = func() {
+		if stopped {
+			return
+		}
+		stopped = true
+		cancel()
+		Eventually(done).WithTimeout(15 * time.Second).Should(Receive(BeNil()))
+		m.stoppedAt = time.Now()
+	}
+	return m
+}
+
+// countingClient counts the HelloWorlds a reconciler reads and the writes it
+// makes to their children in m.
+type countingClient struct {
+	client.Client
+	m *failoverManager
+}
+
+func (c *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if _, ok := obj.(*helloworldv1.HelloWorld); ok {
+		c.m.reads.Add(1)
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *countingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.countWrite(ctx, obj)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *countingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.countWrite(ctx, obj)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *countingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.countWrite(ctx, obj)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *countingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.countWrite(ctx, obj)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *countingClient) countWrite(ctx context.Context, obj client.Object) {
+	if _, ok := obj.(*helloworldv1.HelloWorld); ok {
+		return
+	}
+	now := time.Now()
+	c.m.firstChildWrite.CompareAndSwap(nil, &now)
+	if c.m.gate != nil && obj.GetNamespace() == c.m.namespace {
+		c.m.gatedOnce.Do(func() {
+			close(c.m.gated)
+			select {
+			case <-c.m.gate:
+			case <-ctx.Done():
+			}
+		})
+	}
+	c.m.childWrites.Add(1)
+}
+
+// childResourceVersions returns the resource versions of the children of the
+// HelloWorlds of namespace, by kind and name.
+func childResourceVersions(ctx context.Context, namespace string) map[string]string {
+	GinkgoHelper()
+	versions := map[string]string{}
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels{helloWorldManagedByLabelKey: helloWorldManagedByLabelVal},
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	Expect(k8sClient.List(ctx, configMaps, opts...)).To(Succeed())
+	for _, cm := range configMaps.Items {
+		versions["ConfigMap/"+cm.Name] = cm.ResourceVersion
+	}
+	deployments := &appsv1.DeploymentList{}
+	Expect(k8sClient.List(ctx, deployments, opts...)).To(Succeed())
+	for _, d := range deployments.Items {
+		versions["Deployment/"+d.Name] = d.ResourceVersion
+	}
+	services := &corev1.ServiceList{}
+	Expect(k8sClient.List(ctx, services, opts...)).To(Succeed())
+	for _, s := range services.Items {
+		versions["Service/"+s.Name] = s.ResourceVersion
+	}
+
+	return versions
+}
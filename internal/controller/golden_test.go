@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var update = flag.Bool("update", false, "Update the golden files of rendered HelloWorld children")
+
+var _ = Describe("Rendered HelloWorld children", func() {
+	ctx := context.Background()
+	now := time.Date(2025, time.June, 2, 10, 0, 0, 0, time.UTC)
+
+	// Each input in testdata/render is rendered and compared to its golden
+	// file. Run the tests with -update to rewrite the golden files.
+	DescribeTable("match the golden files",
+		func(input string, golden string, routes bool) {
+			renderScheme := runtime.NewScheme()
+			Expect(helloworldv1.AddToScheme(renderScheme)).To(Succeed())
+
+			data, err := os.ReadFile(filepath.Join("testdata", "render", input))
+			Expect(err).NotTo(HaveOccurred())
+			hw := &helloworldv1.HelloWorld{}
+			Expect(yaml.UnmarshalStrict(data, hw)).To(Succeed())
+
+			objs, err := RenderHelloWorld(ctx, renderScheme, hw, RenderOptions{Now: now, EnableRoutes: routes})
+			Expect(err).NotTo(HaveOccurred())
+
+			var rendered bytes.Buffer
+			for _, obj := range objs {
+				doc, err := yaml.Marshal(obj)
+				Expect(err).NotTo(HaveOccurred())
+				rendered.WriteString("---\n")
+				rendered.Write(doc)
+			}
+
+			path := filepath.Join("testdata", "render", golden)
+			if *update {
+				Expect(os.WriteFile(path, rendered.Bytes(), 0o644)).To(Succeed())
+			}
+			expected, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered.String()).To(Equal(string(expected)))
+		},
+		Entry("a minimal HelloWorld", "minimal.yaml", "minimal.golden.yaml", true),
+		Entry("a minimal HelloWorld without Routes", "minimal.yaml", "minimal-no-routes.golden.yaml", false),
+		Entry("special characters in the message", "special-characters.yaml", "special-characters.golden.yaml", true),
+		Entry("long names", "long-name.yaml", "long-name.golden.yaml", true),
+		Entry("every spec option", "all-options.yaml", "all-options.golden.yaml", true),
+		Entry("every spec option without Routes", "all-options.yaml", "all-options-no-routes.golden.yaml", false),
+		Entry("a blue/green preview", "blue-green-preview.yaml", "blue-green-preview.golden.yaml", true),
+		Entry("a promotion", "promoting.yaml", "promoting.golden.yaml", true),
+		Entry("a pinned revision", "pinned-revision.yaml", "pinned-revision.golden.yaml", true),
+	)
+})
@@ -2,103 +2,473 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
 
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/children"
+	"github.com/opendatahub-io/sample-component/internal/names"
+	"github.com/opendatahub-io/sample-component/internal/policy"
+	"github.com/opendatahub-io/sample-component/internal/tracing"
 )
 
 const (
 	helloWorldAppLabelKey = "app"
 	helloWorldAppLabelVal = "hello-world"
+
+	// helloWorldManagedByLabelKey labels content revisions and children as managed by
+	// the controller, whose cache only holds objects with this label
+	helloWorldManagedByLabelKey = "app.kubernetes.io/managed-by"
+	helloWorldManagedByLabelVal = "helloworld-controller"
+
+	// helloWorldNameLabelKey labels content revisions and children with the HelloWorld they belong to
+	helloWorldNameLabelKey = "helloworld.opendatahub.io/name"
+	// helloWorldContentHashLabelKey labels content revisions with the hash of the page they hold
+	helloWorldContentHashLabelKey = "helloworld.opendatahub.io/content-hash"
+	// helloWorldRevisionAnnotationKey orders content revisions, the highest number being the newest
+	helloWorldRevisionAnnotationKey = "helloworld.opendatahub.io/revision"
+	// helloWorldInventoryAnnotationKey records on a HelloWorld the children created for it
+	helloWorldInventoryAnnotationKey = "helloworld.opendatahub.io/inventory"
+	// helloWorldAdoptAnnotationKey, set to the name of a HelloWorld on an
+	// existing object nothing else controls, lets that HelloWorld adopt it
+	helloWorldAdoptAnnotationKey = "helloworld.opendatahub.io/adopt"
+	// helloWorldLogLevelAnnotationKey, set to debug on a HelloWorld, logs its
+	// reconciles at debug level whatever the level of the controller
+	helloWorldLogLevelAnnotationKey = "helloworld.opendatahub.io/log-level"
+	// helloWorldTrackLabelKey labels nginx pods with the track they belong to
+	helloWorldTrackLabelKey = "helloworld.opendatahub.io/track"
+
+	// helloWorldPort is the port nginx and the Services serve the page on
+	helloWorldPort = 8080
+)
+
+// helloWorldTrack is one of the two nginx Deployments used by blue/green and
+// canary rollouts. Only the stable track exists outside of a rollout.
+type helloWorldTrack string
+
+const (
+	helloWorldStableTrack helloWorldTrack = "stable"
+	helloWorldCanaryTrack helloWorldTrack = "canary"
 )
 
-func reconcileHelloWorldConfigMap(ctx context.Context, cli client.Client, hw *helloworldv1.HelloWorld) error {
-	html := fmt.Sprintf(`
+// helloWorldTrackName returns the name shared by a track's Deployment and
+// Service, and by the Route for the stable track. It is a DNS label, as
+// Service names must be, truncated and hashed for long HelloWorld names.
+func helloWorldTrackName(hw *helloworldv1.HelloWorld, track helloWorldTrack) string {
+	if track == helloWorldCanaryTrack {
+		return names.DNSLabel(hw.Name, "nginx", "canary")
+	}
+	return names.DNSLabel(hw.Name, "nginx")
+}
+
+// helloWorldNameLabel returns the value of the name label of a HelloWorld's
+// content revisions and children, its name unless that is too long for a label.
+func helloWorldNameLabel(hw *helloworldv1.HelloWorld) string {
+	return names.LabelValue(hw.Name)
+}
+
+// helloWorldContentRevisionName returns the name of the content revision
+// holding the page with the given content hash.
+func helloWorldContentRevisionName(hw *helloworldv1.HelloWorld, hash string) string {
+	return names.Child(names.MaxSubdomain, hw.Name, "html", hash)
+}
+
+// helloWorldPodSelector returns the selector of a track's pods. The stable
+// track keeps the app label on its own, since Deployment selectors are
+// immutable, while canary pods use a different app label so the stable
+// Deployment never selects them.
+func helloWorldPodSelector(hw *helloworldv1.HelloWorld, track helloWorldTrack) map[string]string {
+	if track == helloWorldCanaryTrack {
+		return map[string]string{
+			helloWorldAppLabelKey:  helloWorldAppLabelVal + "-canary",
+			helloWorldNameLabelKey: helloWorldNameLabel(hw),
+		}
+	}
+	return map[string]string{
+		helloWorldAppLabelKey: helloWorldAppLabelVal,
+	}
+}
+
+// helloWorldReady reports whether the controller has served the current
+// generation of a HelloWorld and it is available, and if not, why.
+func helloWorldReady(hw *helloworldv1.HelloWorld) (bool, string) {
+	available := meta.FindStatusCondition(hw.Status.Conditions, helloworldv1.ConditionTypeAvailable)
+	switch {
+	case hw.Status.ObservedGeneration != hw.Generation:
+		return false, fmt.Sprintf("Generation %d is not served yet", hw.Generation)
+	case available == nil:
+		return false, "Not available yet"
+	case available.Status != metav1.ConditionTrue:
+		return false, available.Message
+	}
+	return true, ""
+}
+
+func renderHelloWorldHTML(message string) string {
+	return fmt.Sprintf(`
     <!DOCTYPE html>
     <html>
       <head><title>Hello World</title></head>
       <body>
         <h1>%s</h1>
       </body>
-    </html>`, hw.Spec.Message)
+    </html>`, message)
+}
+
+func helloWorldContentHash(html string) string {
+	sum := sha256.Sum256([]byte(html))
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// reconcileHelloWorldContentRevision creates the immutable ConfigMap holding
+// a rendered page and returns its name. Revisions are named after the hash of
+// their content, so rendering a page seen before reuses its revision, which
+// is then renumbered as the newest one. Existing ConfigMaps missing from the
+// cache are read with reader.
+func reconcileHelloWorldContentRevision(
+	ctx context.Context, cli client.Client, reader client.Reader, hw *helloworldv1.HelloWorld, html string,
+) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "reconcileHelloWorldContentRevision",
+		tracing.NameKey.String(hw.Name), tracing.NamespaceKey.String(hw.Namespace), tracing.ChildKindKey.String("ConfigMap"))
+	var result string
+	defer func() { tracing.End(span, result, err) }()
 
-	cm := &corev1.ConfigMap{
+	history, err := listHelloWorldContentRevisions(ctx, cli, hw)
+	if err != nil {
+		return "", err
+	}
+
+	hash := helloWorldContentHash(html)
+	name := helloWorldContentRevisionName(hw, hash)
+	latest := int64(0)
+	if len(history) > 0 {
+		latest = helloWorldContentRevisionNumber(&history[0])
+		if history[0].Name == name {
+			result = "Unchanged"
+			return name, nil
+		}
+	}
+
+	for i := range history {
+		if history[i].Name == name {
+			cm := &history[i]
+			patch := client.MergeFrom(cm.DeepCopy())
+			if cm.Annotations == nil {
+				cm.Annotations = map[string]string{}
+			}
+			cm.Annotations[helloWorldRevisionAnnotationKey] = strconv.FormatInt(latest+1, 10)
+			err = cli.Patch(ctx, cm, patch)
+			if err == nil {
+				result = "Renumbered"
+			}
+			return name, err
+		}
+	}
+
+	cm := helloWorldContentRevision(hw, html, latest+1)
+	err = cli.Create(ctx, cm)
+	if k8serr.IsAlreadyExists(err) {
+		err = adoptHelloWorldContentRevision(ctx, cli, reader, hw, cm)
+		if err == nil {
+			result = "Adopted"
+		}
+		return cm.Name, err
+	}
+	if err != nil {
+		return "", err
+	}
+	result = "Created"
+
+	return cm.Name, nil
+}
+
+// adoptHelloWorldContentRevision adopts an existing ConfigMap named like the
+// desired content revision if it is annotated for adoption by the HelloWorld
+// and holds the same page, and returns a conflict otherwise. Revisions of the
+// HelloWorld that are not cached, because they were created before revisions
+// carried the managed-by label, are labelled and renumbered as the newest.
+func adoptHelloWorldContentRevision(
+	ctx context.Context, cli client.Client, reader client.Reader, hw *helloworldv1.HelloWorld, desired *corev1.ConfigMap,
+) error {
+	cm := &corev1.ConfigMap{}
+	err := reader.Get(ctx, client.ObjectKeyFromObject(desired), cm)
+	if err != nil {
+		return err
+	}
+	err = children.CheckController("ConfigMap", cm, hw, helloWorldAdoptAnnotationKey)
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(cm, hw) && !equality.Semantic.DeepEqual(cm.Data, desired.Data) {
+		return fmt.Errorf("cannot adopt ConfigMap %s, which does not hold the rendered page: %w",
+			cm.Name, &children.ConflictError{Kind: "ConfigMap", Name: cm.Name})
+	}
+
+	patch := client.MergeFrom(cm.DeepCopy())
+	if cm.Labels == nil {
+		cm.Labels = map[string]string{}
+	}
+	maps.Copy(cm.Labels, desired.Labels)
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	maps.Copy(cm.Annotations, desired.Annotations)
+	err = controllerutil.SetControllerReference(hw, cm, cli.Scheme())
+	if err != nil {
+		return err
+	}
+
+	return cli.Patch(ctx, cm, patch)
+}
+
+// helloWorldContentRevision returns the immutable ConfigMap holding a
+// rendered page as the given revision number.
+func helloWorldContentRevision(hw *helloworldv1.HelloWorld, html string, number int64) *corev1.ConfigMap {
+	hash := helloWorldContentHash(html)
+	name := helloWorldContentRevisionName(hw, hash)
+
+	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ConfigMap",
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-html", hw.Name),
+			Name:      name,
 			Namespace: hw.Namespace,
+			Labels: map[string]string{
+				helloWorldManagedByLabelKey:   helloWorldManagedByLabelVal,
+				helloWorldNameLabelKey:        helloWorldNameLabel(hw),
+				helloWorldContentHashLabelKey: hash,
+			},
+			Annotations: map[string]string{
+				helloWorldRevisionAnnotationKey: strconv.FormatInt(number, 10),
+			},
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					APIVersion: hw.APIVersion,
 					Kind:       hw.Kind,
 					Name:       hw.Name,
 					UID:        hw.UID,
+					Controller: ptr.To(true),
 				},
 			},
 		},
+		Immutable: ptr.To(true),
 		Data: map[string]string{
 			"index.html": html,
 		},
 	}
+}
+
+// listHelloWorldContentRevisions returns the metadata of the content
+// revisions of a HelloWorld, newest first. Only their metadata is cached,
+// since the pages they hold are only read by nginx.
+func listHelloWorldContentRevisions(ctx context.Context, cli client.Client, hw *helloworldv1.HelloWorld) ([]metav1.PartialObjectMetadata, error) {
+	revisions := &metav1.PartialObjectMetadataList{}
+	revisions.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMapList"))
+	err := cli.List(ctx, revisions, client.InNamespace(hw.Namespace), client.MatchingLabels{
+		helloWorldNameLabelKey: helloWorldNameLabel(hw),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]metav1.PartialObjectMetadata, 0, len(revisions.Items))
+	for _, cm := range revisions.Items {
+		if metav1.IsControlledBy(&cm, hw) {
+			history = append(history, cm)
+		}
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return helloWorldContentRevisionNumber(&history[i]) > helloWorldContentRevisionNumber(&history[j])
+	})
+
+	return history, nil
+}
+
+func helloWorldContentRevisionNumber(cm metav1.Object) int64 {
+	n, _ := strconv.ParseInt(cm.GetAnnotations()[helloWorldRevisionAnnotationKey], 10, 64)
+	return n
+}
+
+// getHelloWorldContentRevision returns the metadata of the named content
+// revision, failing if it does not exist or belongs to another HelloWorld.
+// Revisions missing from the cache are read with reader.
+func getHelloWorldContentRevision(
+	ctx context.Context, cli client.Client, reader client.Reader, hw *helloworldv1.HelloWorld, name string,
+) (*metav1.PartialObjectMetadata, error) {
+	cm := &metav1.PartialObjectMetadata{}
+	cm.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	key := client.ObjectKey{Name: name, Namespace: hw.Namespace}
+	err := cli.Get(ctx, key, cm)
+	if k8serr.IsNotFound(err) {
+		err = reader.Get(ctx, key, cm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cm.Labels[helloWorldNameLabelKey] != helloWorldNameLabel(hw) || !metav1.IsControlledBy(cm, hw) {
+		return nil, fmt.Errorf("ConfigMap %s is not a content revision of HelloWorld %s", name, hw.Name)
+	}
 
-	err := cli.Create(ctx, cm)
-	if err != nil && !k8serr.IsAlreadyExists(err) {
+	return cm, nil
+}
+
+// pruneHelloWorldContentRevisions deletes the oldest content revisions beyond
+// the HelloWorld's revision history limit. Revisions listed in keep, such as
+// the active and last known-good ones, are never deleted and do not count
+// towards the limit.
+func pruneHelloWorldContentRevisions(ctx context.Context, cli client.Client, hw *helloworldv1.HelloWorld, keep ...string) error {
+	history, err := listHelloWorldContentRevisions(ctx, cli, hw)
+	if err != nil {
 		return err
 	}
 
+	limit := int(ptr.Deref(hw.Spec.RevisionHistoryLimit, helloworldv1.DefaultRevisionHistoryLimit))
+	for _, cm := range history {
+		if slices.Contains(keep, cm.Name) {
+			continue
+		}
+		if limit > 0 {
+			limit--
+			continue
+		}
+		err = cli.Delete(ctx, &cm)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func reconcileHelloWorldDeployment(ctx context.Context, cli client.Client, hw *helloworldv1.HelloWorld) error {
-	deployment := &appsv1.Deployment{
+// helloWorldRoutes reports whether a HelloWorld is exposed through a
+// Route, which takes the Route exposure, the default one, and a cluster
+// serving route.openshift.io.
+func helloWorldRoutes(hw *helloworldv1.HelloWorld, enableRoutes bool) bool {
+	return enableRoutes && policy.Exposure(hw, nil) == helloworldv1.RouteExposureType
+}
+
+// helloWorldState is what a reconcile derives from a HelloWorld before
+// reconciling its children.
+type helloWorldState struct {
+	hw   *helloworldv1.HelloWorld
+	plan helloWorldRolloutPlan
+	// routeAPI tells whether the cluster serves Routes, and routes whether
+	// the HelloWorld is exposed through one
+	routeAPI bool
+	routes   bool
+
+	// stable is the stable track Deployment as last returned by the API
+	// server, including its rollout status
+	stable *appsv1.Deployment
+}
+
+// helloWorldChildren are the objects a HelloWorld owns besides its content
+// revisions. Children are applied in order and pruned in reverse order, so
+// the canary track exists before any traffic is sent to it and is only
+// removed once no traffic reaches it anymore.
+var helloWorldChildren = &children.Set[*helloWorldState]{
+	InventoryLabel:      helloWorldNameLabelKey,
+	InventoryAnnotation: helloWorldInventoryAnnotationKey,
+	AdoptAnnotation:     helloWorldAdoptAnnotationKey,
+	Labels: map[string]string{
+		helloWorldManagedByLabelKey: helloWorldManagedByLabelVal,
+	},
+	Children: []children.Reconciler[*helloWorldState]{
+		&children.Child[*helloWorldState, *appsv1.Deployment]{
+			Kind:    "Deployment",
+			New:     func() *appsv1.Deployment { return &appsv1.Deployment{} },
+			Desired: desiredHelloWorldDeployments,
+			Mutate:  mutateHelloWorldDeployment,
+			Applied: func(s *helloWorldState, deployment *appsv1.Deployment) {
+				if deployment.Name == helloWorldTrackName(s.hw, helloWorldStableTrack) {
+					s.stable = deployment
+				}
+			},
+			Ready: helloWorldDeploymentsReady,
+		},
+		&children.Child[*helloWorldState, *corev1.Service]{
+			Kind:    "Service",
+			New:     func() *corev1.Service { return &corev1.Service{} },
+			Desired: desiredHelloWorldServices,
+			Mutate:  mutateHelloWorldService,
+		},
+		&children.Child[*helloWorldState, *routev1.Route]{
+			Kind:    "Route",
+			New:     func() *routev1.Route { return &routev1.Route{} },
+			Served:  func(s *helloWorldState) bool { return s.routeAPI },
+			Desired: desiredHelloWorldRoutes,
+			Mutate:  mutateHelloWorldRoute,
+		},
+	},
+}
+
+// desiredHelloWorldDeployments returns the stable track Deployment, and the
+// canary track Deployment while a candidate revision is served.
+func desiredHelloWorldDeployments(_ context.Context, s *helloWorldState) ([]*appsv1.Deployment, error) {
+	deployments := []*appsv1.Deployment{
+		helloWorldDeployment(s.hw, helloWorldStableTrack, s.plan.stable, s.plan.stableReplicas),
+	}
+	if s.plan.canary != nil {
+		deployments = append(deployments,
+			helloWorldDeployment(s.hw, helloWorldCanaryTrack, *s.plan.canary, s.plan.canaryReplicas))
+	}
+
+	return deployments, nil
+}
+
+func helloWorldDeployment(
+	hw *helloworldv1.HelloWorld, track helloWorldTrack, revision helloworldv1.HelloWorldRevision, replicas int32,
+) *appsv1.Deployment {
+	labels := helloWorldPodSelector(hw, track)
+	labels[helloWorldNameLabelKey] = helloWorldNameLabel(hw)
+	labels[helloWorldTrackLabelKey] = string(track)
+
+	return &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Deployment",
 			APIVersion: "apps/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-nginx", hw.Name),
+			Name:      helloWorldTrackName(hw, track),
 			Namespace: hw.Namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: hw.APIVersion,
-					Kind:       hw.Kind,
-					Name:       hw.Name,
-					UID:        hw.UID,
-				},
-			},
 		},
 		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(replicas),
 			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					helloWorldAppLabelKey: helloWorldAppLabelVal,
-				},
+				MatchLabels: helloWorldPodSelector(hw, track),
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						helloWorldAppLabelKey: helloWorldAppLabelVal,
-					},
+					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
 						{
 							Name:  "nginx",
-							Image: "nginxinc/nginx-unprivileged:latest",
+							Image: revision.Image,
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "http",
-									ContainerPort: 8080,
+									ContainerPort: helloWorldPort,
 								},
 							},
 							VolumeMounts: []corev1.VolumeMount{
@@ -116,7 +486,7 @@ func reconcileHelloWorldDeployment(ctx context.Context, cli client.Client, hw *h
 							VolumeSource: corev1.VolumeSource{
 								ConfigMap: &corev1.ConfigMapVolumeSource{
 									LocalObjectReference: corev1.LocalObjectReference{
-										Name: fmt.Sprintf("%s-html", hw.Name),
+										Name: revision.Content,
 									},
 								},
 							},
@@ -126,92 +496,192 @@ func reconcileHelloWorldDeployment(ctx context.Context, cli client.Client, hw *h
 			},
 		},
 	}
+}
 
-	err := cli.Create(ctx, deployment)
-	if err != nil && !k8serr.IsAlreadyExists(err) {
-		return err
+// mutateHelloWorldDeployment points the nginx container of an existing
+// Deployment at the desired image and content revision, so that a newly
+// resolved digest or changed message rolls out, and scales it to the desired
+// replicas. Only the ConfigMap name of the html volume is compared, since the
+// API server defaults the rest of the volume source.
+func mutateHelloWorldDeployment(deployment *appsv1.Deployment, desired *appsv1.Deployment) {
+	deployment.Spec.Replicas = desired.Spec.Replicas
+	for k, v := range desired.Spec.Template.Labels {
+		if deployment.Spec.Template.Labels == nil {
+			deployment.Spec.Template.Labels = map[string]string{}
+		}
+		deployment.Spec.Template.Labels[k] = v
+	}
+	image := desired.Spec.Template.Spec.Containers[0].Image
+	for i := range deployment.Spec.Template.Spec.Containers {
+		c := &deployment.Spec.Template.Spec.Containers[i]
+		if c.Name == "nginx" {
+			c.Image = image
+		}
+	}
+	content := desired.Spec.Template.Spec.Volumes[0].ConfigMap.Name
+	for i := range deployment.Spec.Template.Spec.Volumes {
+		v := &deployment.Spec.Template.Spec.Volumes[i]
+		if v.Name == "html" && (v.ConfigMap == nil || v.ConfigMap.Name != content) {
+			v.VolumeSource = desired.Spec.Template.Spec.Volumes[0].VolumeSource
+		}
+	}
+}
+
+// helloWorldDeploymentsReady reports whether every track has an available replica.
+func helloWorldDeploymentsReady(deployments []*appsv1.Deployment) (bool, string) {
+	for _, deployment := range deployments {
+		if ptr.Deref(deployment.Spec.Replicas, 1) > 0 && deployment.Status.AvailableReplicas == 0 {
+			return false, fmt.Sprintf("Deployment %s has no available replicas", deployment.Name)
+		}
 	}
 
-	return nil
+	return true, "All nginx Deployments have available replicas"
+}
+
+// helloWorldDeploymentServes reports whether the spec of a Deployment
+// already serves a revision.
+func helloWorldDeploymentServes(deployment *appsv1.Deployment, revision helloworldv1.HelloWorldRevision) bool {
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		if c.Name == "nginx" && c.Image != revision.Image {
+			return false
+		}
+	}
+	for _, v := range deployment.Spec.Template.Spec.Volumes {
+		if v.Name == "html" {
+			return v.ConfigMap != nil && v.ConfigMap.Name == revision.Content
+		}
+	}
+
+	return false
+}
+
+// helloWorldRolloutStatus summarises the rollout of the nginx Deployment. A
+// rollout is complete once the Deployment controller has observed the latest
+// spec and every replica runs it and is available. It has failed when the
+// Deployment reports ProgressDeadlineExceeded.
+func helloWorldRolloutStatus(deployment *appsv1.Deployment) (rollout helloworldv1.RolloutStatus, complete bool, failed bool) {
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	rollout = helloworldv1.RolloutStatus{
+		ObservedGeneration: deployment.Status.ObservedGeneration,
+		Replicas:           replicas,
+		UpdatedReplicas:    deployment.Status.UpdatedReplicas,
+		AvailableReplicas:  deployment.Status.AvailableReplicas,
+	}
+
+	observed := deployment.Status.ObservedGeneration >= deployment.Generation
+	if !observed {
+		return rollout, false, false
+	}
+
+	for _, c := range deployment.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse &&
+			c.Reason == "ProgressDeadlineExceeded" {
+			return rollout, false, true
+		}
+	}
+
+	complete = deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+
+	return rollout, complete, false
+}
+
+// desiredHelloWorldServices returns the stable track Service, and the canary
+// track Service while a candidate revision is served.
+func desiredHelloWorldServices(_ context.Context, s *helloWorldState) ([]*corev1.Service, error) {
+	services := []*corev1.Service{
+		helloWorldService(s.hw, helloWorldStableTrack, s.plan.stableServiceSelector(s.hw)),
+	}
+	if s.plan.canary != nil {
+		services = append(services,
+			helloWorldService(s.hw, helloWorldCanaryTrack, helloWorldPodSelector(s.hw, helloWorldCanaryTrack)))
+	}
+
+	return services, nil
 }
 
-func reconcileHelloWorldService(ctx context.Context, cli client.Client, hw *helloworldv1.HelloWorld) error {
-	service := &corev1.Service{
+func helloWorldService(hw *helloworldv1.HelloWorld, track helloWorldTrack, selector map[string]string) *corev1.Service {
+	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Service",
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-nginx", hw.Name),
+			Name:      helloWorldTrackName(hw, track),
 			Namespace: hw.Namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: hw.APIVersion,
-					Kind:       hw.Kind,
-					Name:       hw.Name,
-					UID:        hw.UID,
-				},
-			},
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: map[string]string{
-				helloWorldAppLabelKey: helloWorldAppLabelVal,
-			},
+			Selector: selector,
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "http",
 					Protocol:   "TCP",
-					Port:       8080,
-					TargetPort: intstr.FromInt32(8080),
+					Port:       helloWorldPort,
+					TargetPort: intstr.FromInt32(helloWorldPort),
 				},
 			},
 		},
 	}
+}
 
-	err := cli.Create(ctx, service)
-	if err != nil && !k8serr.IsAlreadyExists(err) {
-		return err
-	}
-
-	return nil
+// mutateHelloWorldService points an existing Service at the desired pods,
+// which is how traffic shifts between tracks when Routes are not available.
+func mutateHelloWorldService(service *corev1.Service, desired *corev1.Service) {
+	service.Spec.Selector = desired.Spec.Selector
 }
 
-func reconcileHelloWorldRoute(ctx context.Context, cli client.Client, hw *helloworldv1.HelloWorld) error {
+// desiredHelloWorldRoutes returns the Route exposing the stable Service,
+// which sends canaryWeight percent of the traffic to the canary Service while
+// a candidate revision is served. There is none for HelloWorlds exposed
+// otherwise, so a Route left from a previous exposure is pruned.
+func desiredHelloWorldRoutes(_ context.Context, s *helloWorldState) ([]*routev1.Route, error) {
+	if !s.routes {
+		return nil, nil
+	}
+	hw := s.hw
+	var alternateBackends []routev1.RouteTargetReference
+	if s.plan.canary != nil {
+		alternateBackends = []routev1.RouteTargetReference{
+			{
+				Kind:   "Service",
+				Name:   helloWorldTrackName(hw, helloWorldCanaryTrack),
+				Weight: ptr.To(s.plan.canaryWeight),
+			},
+		}
+	}
+
 	route := &routev1.Route{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Route",
 			APIVersion: "route.openshift.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-nginx", hw.Name),
+			Name:      helloWorldTrackName(hw, helloWorldStableTrack),
 			Namespace: hw.Namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: hw.APIVersion,
-					Kind:       hw.Kind,
-					Name:       hw.Name,
-					UID:        hw.UID,
-				},
-			},
 		},
 		Spec: routev1.RouteSpec{
 			Port: &routev1.RoutePort{
-				TargetPort: intstr.FromInt32(8080),
+				TargetPort: intstr.FromInt32(helloWorldPort),
 			},
 			To: routev1.RouteTargetReference{
-				Kind: "Service",
-				Name: fmt.Sprintf("%s-nginx", hw.Name),
+				Kind:   "Service",
+				Name:   helloWorldTrackName(hw, helloWorldStableTrack),
+				Weight: ptr.To(100 - s.plan.canaryWeight),
 			},
+			AlternateBackends: alternateBackends,
 			TLS: &routev1.TLSConfig{
 				Termination: routev1.TLSTerminationEdge,
 			},
 		},
 	}
 
-	err := cli.Create(ctx, route)
-	if err != nil && !k8serr.IsAlreadyExists(err) {
-		return err
-	}
+	return []*routev1.Route{route}, nil
+}
 
-	return nil
+// mutateHelloWorldRoute brings the backends and weights of an existing Route
+// in line with the desired ones.
+func mutateHelloWorldRoute(route *routev1.Route, desired *routev1.Route) {
+	route.Spec.To = desired.Spec.To
+	route.Spec.AlternateBackends = desired.Spec.AlternateBackends
 }
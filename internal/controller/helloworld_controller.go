@@ -18,35 +18,164 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	routev1 "github.com/openshift/api/route/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/children"
+	"github.com/opendatahub-io/sample-component/internal/image"
+	"github.com/opendatahub-io/sample-component/internal/logging"
+	"github.com/opendatahub-io/sample-component/internal/policy"
+	"github.com/opendatahub-io/sample-component/internal/probe"
+	"github.com/opendatahub-io/sample-component/internal/tracing"
 )
 
+// DefaultHelloWorldImage is the nginx image used when a HelloWorld does not set spec.image
+const DefaultHelloWorldImage = "nginxinc/nginx-unprivileged:latest"
+
 // HelloWorldReconciler reconciles a HelloWorld object
 type HelloWorldReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// APIReader reads objects missing from the cache, which only holds the
+	// ConfigMaps, Deployments, Services and Routes labelled as managed by
+	// the controller. Defaults to the Client.
+	APIReader client.Reader
+
+	// Resolver resolves image tags to the digests Deployments are pinned to
+	Resolver image.Resolver
+	// AllowedRegistries lists the registries, or registry/repository prefixes,
+	// HelloWorld images may be pulled from. An empty list allows any registry.
+	AllowedRegistries []string
+	// Clock tells the time scheduled messages are evaluated at
+	Clock clock.PassiveClock
+	// EnableRoutes exposes HelloWorlds through OpenShift Routes, and is set
+	// when the cluster serves the route.openshift.io API. Without Routes,
+	// canary traffic is split by replica counts behind the Service.
+	EnableRoutes bool
+	// DryRun computes the children of each HelloWorld and applies them with
+	// server-side dry-run, reporting what would change in the logs and in
+	// status.plannedChanges instead of changing anything
+	DryRun bool
+	// Prober fetches the page served by the stable Service every
+	// ProbeInterval, to verify it is the content of a served revision. The
+	// content is not probed if either is unset.
+	Prober        probe.Prober
+	ProbeInterval time.Duration
+	// Quota limits the HelloWorlds of every namespace, on top of the quotas
+	// of HelloWorldPolicies. HelloWorlds over it are reported, not stopped.
+	Quota *helloworldv1.NamespaceQuota
+
+	// MaxConcurrentReconciles is the number of HelloWorlds reconciled in
+	// parallel, 1 if unset
+	MaxConcurrentReconciles int
+	// RateLimiter limits how often HelloWorlds are requeued, the
+	// controller-runtime default if unset
+	RateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+	// ReconcileTimeout bounds the time a single reconcile may take,
+	// unbounded if zero
+	ReconcileTimeout time.Duration
+	// DrainTimeout is how long a reconcile in flight when the manager stops
+	// may carry on, so that it finishes writing the children of its
+	// HelloWorld before the manager releases its leader election lease.
+	// Reconciles are cancelled as soon as the manager stops if zero.
+	DrainTimeout time.Duration
+	// TracerProvider traces reconciles, the global OpenTelemetry provider
+	// if unset
+	TracerProvider trace.TracerProvider
+}
+
+// NewRateLimiter returns a rate limiter for HelloWorld requests that delays
+// each request by the longer of an exponential backoff per HelloWorld, from
+// baseDelay up to maxDelay as it keeps failing, and a token bucket shared by
+// all HelloWorlds, refilled at qps up to burst tokens.
+func NewRateLimiter(baseDelay, maxDelay time.Duration, qps float64, burst int) workqueue.TypedRateLimiter[reconcile.Request] {
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, maxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
 }
 
 // +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworlds,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworlds/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworlds/finalizers,verbs=update
 
-// +kubebuilder:rbac:groups="",resources=configmaps;services,verbs=create
-// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=create
-// +kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=create
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=get;list;watch;create;patch;delete
 
 func (r *HelloWorldReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	// Create a logger with the HelloWorld CR's name to keep track
-	logger := log.FromContext(ctx).WithName(req.Name)
+	provider := r.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	ctx, span := provider.Tracer(tracing.TracerName).Start(ctx, "HelloWorldReconciler.Reconcile",
+		trace.WithAttributes(tracing.NameKey.String(req.Name), tracing.NamespaceKey.String(req.Namespace)))
+
+	result, err := r.reconcileHelloWorld(ctx, req)
+	switch {
+	case err != nil:
+		tracing.End(span, "", err)
+	case result.Requeue:
+		tracing.End(span, "Requeue", nil)
+	case result.RequeueAfter > 0:
+		tracing.End(span, "RequeueAfter", nil)
+	default:
+		tracing.End(span, "Done", nil)
+	}
+
+	return result, err
+}
 
-	logger.Info(fmt.Sprintf("Reconciling HelloWorld %s in namespace %s", req.Name, req.Namespace))
+// reconcileHelloWorld brings the children of the requested HelloWorld in line
+// with its spec and reports their state in its status.
+func (r *HelloWorldReconciler) reconcileHelloWorld(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// The controller logs reconciles with the name, namespace and reconcileID
+	// of the request
+	logger := log.FromContext(ctx)
+
+	if r.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = drainContext(ctx, r.DrainTimeout)
+		defer cancel()
+	}
+	if r.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ReconcileTimeout)
+		defer cancel()
+	}
 
 	// Capture the name and namespace of the incoming Request object
 	ref := client.ObjectKey{
@@ -58,44 +187,659 @@ func (r *HelloWorldReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// Get the HelloWorld CR
 	err := r.Client.Get(ctx, ref, hw)
 	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Log with the generation, and at the level the HelloWorld asks for
+	logger = logger.WithValues("generation", hw.Generation)
+	if level, ok := hw.Annotations[helloWorldLogLevelAnnotationKey]; ok {
+		verbosity, err := logging.ParseLevel(level)
+		if err != nil {
+			logger.Error(err, "Ignoring invalid log level annotation", "annotation", helloWorldLogLevelAnnotationKey)
+		} else {
+			logger = logging.WithVerbosity(logger, verbosity)
+		}
+	}
+	ctx = log.IntoContext(ctx, logger)
+	logger.Info("Reconciling HelloWorld")
+
+	// Serve the HelloWorld with the defaults, and within the limits, of the
+	// HelloWorldPolicies applying to its namespace. They are only applied in
+	// memory, the stored spec is left as written
+	applied, err := policy.Effective(ctx, r.Client, hw.Namespace)
+	if err != nil {
+		logger.Error(err, "Failed to get HelloWorldPolicies")
+		return ctrl.Result{}, err
+	}
+	violations := policy.Validate(hw, applied, DefaultHelloWorldImage)
+	quota := policy.Quota(applied, r.Quota)
+	overQuota, err := policy.CheckQuota(ctx, r.Client, hw, quota)
+	if err != nil {
+		logger.Error(err, "Failed to check HelloWorld quota")
+		return ctrl.Result{}, err
+	}
+	applyPolicy(hw, applied)
+	routes := helloWorldRoutes(hw, r.EnableRoutes)
+
+	// In dry-run mode every write goes through a dry-run client, and only
+	// the planned changes are reported on the unmodified HelloWorld
+	original := hw.DeepCopy()
+	cli := r.Client
+	if r.DryRun {
+		cli = client.NewDryRunClient(r.Client)
+	}
+	var planned []helloworldv1.PlannedChange
+
+	// Resolve the nginx image to the digest the Deployment is pinned to
+	statusPatch := client.MergeFrom(hw.DeepCopy())
+	hw.Status.Policy = applied
+	r.reportPolicyCompliance(hw, violations)
+	r.reportQuota(hw, quota, overQuota)
+	pinnedImage, err := r.resolveImage(ctx, hw)
+	if err != nil {
+		logger.Error(err, "Failed to resolve HelloWorld image")
+		if len(violations) > 0 && !r.DryRun {
+			if patchErr := r.Status().Patch(ctx, hw, statusPatch); patchErr != nil {
+				logger.Error(patchErr, "Failed to update HelloWorld status")
+			}
+		}
 		return ctrl.Result{}, err
 	}
 
-	// Create ConfigMap
-	err = reconcileHelloWorldConfigMap(ctx, r.Client, hw)
+	// Work out which message the schedule serves right now
+	now := r.Clock.Now()
+	message, activeEntry, nextTransition, err := scheduledMessage(hw, now)
+	if err != nil {
+		r.Recorder.Event(hw, corev1.EventTypeWarning, "InvalidSchedule", err.Error())
+		logger.Error(err, "Failed to evaluate HelloWorld schedule")
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
+	hw.Status.Schedule = scheduleStatus(hw, activeEntry, nextTransition)
+
+	// Work out which revision to serve: the last known-good one if this
+	// generation was rolled back, a pinned one, or the rendered message
+	revision := helloworldv1.HelloWorldRevision{
+		Image: pinnedImage,
+	}
+	rolledBack := meta.FindStatusCondition(hw.Status.Conditions, helloworldv1.ConditionTypeRolledBack)
+	rendered := false
+	switch {
+	case rolledBack != nil && rolledBack.Status == metav1.ConditionTrue &&
+		rolledBack.ObservedGeneration == hw.Generation && hw.Status.LastKnownGood != nil:
+		revision = *hw.Status.LastKnownGood
+
+	case hw.Spec.Revision != "":
+		revision.Content = hw.Spec.Revision
+
+	default:
+		// Create ConfigMap
+		rendered = true
+		revision.Content, err = reconcileHelloWorldContentRevision(ctx, cli, r.apiReader(), hw, renderHelloWorldHTML(message))
+		if err != nil {
+			logger.Error(err, "Failed to reconcile HelloWorld ConfigMap")
+			if r.reportChildConflict(hw, err) && !r.DryRun {
+				if patchErr := r.Status().Patch(ctx, hw, statusPatch); patchErr != nil {
+					logger.Error(patchErr, "Failed to update HelloWorld status")
+				}
+			}
+			return ctrl.Result{}, err
+		}
+	}
+	if rolledBack != nil && rolledBack.ObservedGeneration != hw.Generation && rolledBack.Status == metav1.ConditionTrue {
+		meta.SetStatusCondition(&hw.Status.Conditions, metav1.Condition{
+			Type:               helloworldv1.ConditionTypeRolledBack,
+			Status:             metav1.ConditionFalse,
+			Reason:             "SpecChanged",
+			Message:            "Serving the current spec",
+			ObservedGeneration: hw.Generation,
+		})
+	}
+
+	_, err = getHelloWorldContentRevision(ctx, r.Client, r.apiReader(), hw, revision.Content)
+	if r.DryRun && rendered && k8serr.IsNotFound(err) {
+		// Rendered content that is not in the history yet is a revision to create
+		planned = append(planned, helloworldv1.PlannedChange{
+			Action: string(children.ActionCreate),
+			Kind:   "ConfigMap",
+			Name:   revision.Content,
+		})
+		err = nil
+	}
 	if err != nil {
-		logger.Error(err, "Failed to reconcile HelloWorld ConfigMap")
+		r.Recorder.Eventf(hw, corev1.EventTypeWarning, "RevisionNotFound", "Content revision %s: %v", revision.Content, err)
+		logger.Error(err, "Failed to get HelloWorld content revision", "revision", revision.Content)
+		var apiErr k8serr.APIStatus
+		if k8serr.IsNotFound(err) || !errors.As(err, &apiErr) {
+			err = reconcile.TerminalError(err)
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Decide what the stable and canary tracks serve
+	plan := planHelloWorldRollout(hw, revision, routes)
+	hw.Status.ActiveRevision = plan.stable.Content
+	logger.V(logging.DebugLevel).Info("Planned rollout", "revision", revision.Content, "phase", plan.phase,
+		"stableRevision", plan.stable.Content, "canaryWeight", plan.canaryWeight)
+
+	// A promotion is over once the stable track has rolled out the promoted
+	// revision, after which the canary track is no longer needed
+	stable := &appsv1.Deployment{}
+	err = r.Get(ctx, client.ObjectKey{Name: helloWorldTrackName(hw, helloWorldStableTrack), Namespace: hw.Namespace}, stable)
+	if client.IgnoreNotFound(err) != nil {
+		logger.Error(err, "Failed to get HelloWorld Deployment")
 		return ctrl.Result{}, err
 	}
+	if _, complete, _ := helloWorldRolloutStatus(stable); err == nil && complete && helloWorldDeploymentServes(stable, plan.stable) {
+		plan.settle()
+	}
 
-	// Create Deployment
-	err = reconcileHelloWorldDeployment(ctx, r.Client, hw)
+	// Create the Deployments, Services and Route, and remove the ones no
+	// longer needed
+	state := &helloWorldState{hw: hw, plan: plan, routeAPI: r.EnableRoutes, routes: routes}
+	childResult, err := helloWorldChildren.WithAPIReader(r.apiReader()).Reconcile(ctx, cli, hw, state)
+	if r.DryRun {
+		if err != nil {
+			logger.Error(err, "Failed to dry-run HelloWorld children")
+			return ctrl.Result{}, err
+		}
+		for _, change := range childResult.Changes {
+			planned = append(planned, helloworldv1.PlannedChange{
+				Action: string(change.Action),
+				Kind:   change.Kind,
+				Name:   change.Name,
+				Diff:   change.Diff,
+			})
+		}
+		return ctrl.Result{}, r.reportPlannedChanges(ctx, original, planned)
+	}
+	for _, change := range childResult.Changes {
+		logger.V(logging.DebugLevel).Info("Changed child", "action", change.Action, "kind", change.Kind, "child", change.Name)
+	}
+	for _, condition := range childResult.Conditions {
+		meta.SetStatusCondition(&hw.Status.Conditions, condition)
+	}
+	r.reportChildConflict(hw, err)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile HelloWorld Deployment")
+		logger.Error(err, "Failed to reconcile HelloWorld children")
+		if patchErr := r.Status().Patch(ctx, hw, statusPatch); patchErr != nil {
+			logger.Error(patchErr, "Failed to update HelloWorld status")
+		}
 		return ctrl.Result{}, err
 	}
+	hw.Status.Strategy = plan.status(hw)
 
-	// Create Service
-	err = reconcileHelloWorldService(ctx, r.Client, hw)
+	// Track the rollout and roll back if it failed
+	result := r.trackRollout(hw, state.stable, plan.stable)
+
+	// Check that the Service serves the content it should
+	verifyAfter := r.verifyContent(ctx, hw, state.stable, plan)
+
+	// Garbage collect old content revisions
+	keep := []string{revision.Content, plan.stable.Content, hw.Spec.Revision}
+	if hw.Status.LastKnownGood != nil {
+		keep = append(keep, hw.Status.LastKnownGood.Content)
+	}
+	err = pruneHelloWorldContentRevisions(ctx, r.Client, hw, keep...)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile HelloWorld Service")
+		logger.Error(err, "Failed to prune HelloWorld content revisions")
 		return ctrl.Result{}, err
 	}
 
-	// Create Route
-	err = reconcileHelloWorldRoute(ctx, r.Client, hw)
+	// Record the pinned digest and rollout progress
+	hw.Status.ObservedGeneration = hw.Generation
+	hw.Status.PlannedChanges = nil
+	err = r.Status().Patch(ctx, hw, statusPatch)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile HelloWorld Route")
+		logger.Error(err, "Failed to update HelloWorld status")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Come back when the schedule switches content, or to probe it again
+	if nextTransition != nil && !result.Requeue {
+		after := nextTransition.Sub(now)
+		if result.RequeueAfter == 0 || after < result.RequeueAfter {
+			result.RequeueAfter = after
+		}
+	}
+	if verifyAfter > 0 && !result.Requeue && (result.RequeueAfter == 0 || verifyAfter < result.RequeueAfter) {
+		result.RequeueAfter = verifyAfter
+	}
+	if result.Requeue || result.RequeueAfter > 0 {
+		logger.V(logging.DebugLevel).Info("Requeueing HelloWorld", "after", result.RequeueAfter)
+	}
+
+	return result, nil
+}
+
+// apiReader returns the reader of objects missing from the cache.
+func (r *HelloWorldReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// reportChildConflict sets the ChildConflict condition, true when err is a
+// conflict with an existing object the HelloWorld does not control. It
+// reports whether it was.
+func (r *HelloWorldReconciler) reportChildConflict(hw *helloworldv1.HelloWorld, err error) bool {
+	condition := metav1.Condition{
+		Type:               helloworldv1.ConditionTypeChildConflict,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoConflict",
+		Message:            "All children are controlled by the HelloWorld",
+		ObservedGeneration: hw.Generation,
+	}
+	var conflict *children.ConflictError
+	if errors.As(err, &conflict) {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "NotControlled"
+		condition.Message = fmt.Sprintf("%v. Annotate it with %s=%s to adopt it, or delete it",
+			err, helloWorldAdoptAnnotationKey, hw.Name)
+		if conflict.Controller != nil {
+			condition.Message = fmt.Sprintf("%v. It cannot be adopted while it has another controller", err)
+		}
+		r.Recorder.Event(hw, corev1.EventTypeWarning, "ChildConflict", condition.Message)
+	} else if err != nil {
+		return false
+	}
+	meta.SetStatusCondition(&hw.Status.Conditions, condition)
+
+	return condition.Status == metav1.ConditionTrue
+}
+
+// applyPolicy defaults the image and exposure of hw to the ones of the
+// policy, and brings its replicas and exposure within the policy limits. A
+// HelloWorld whose exposure is not allowed is only exposed through its
+// Service.
+func applyPolicy(hw *helloworldv1.HelloWorld, p *helloworldv1.AppliedPolicy) {
+	if p != nil && hw.Spec.Image == "" {
+		hw.Spec.Image = p.DefaultImage
+	}
+	if p != nil && p.MaxReplicas != nil && ptr.Deref(hw.Spec.Replicas, 1) > *p.MaxReplicas {
+		hw.Spec.Replicas = ptr.To(*p.MaxReplicas)
+	}
+	hw.Spec.Exposure = policy.Exposure(hw, p)
+	if !policy.ExposureAllowed(p, hw.Spec.Exposure) {
+		hw.Spec.Exposure = helloworldv1.ServiceExposureType
+	}
+}
+
+// reportPolicyCompliance sets the PolicyCompliant condition from the
+// violations of the policy applied to hw, and removes it when no policy
+// applies. A Warning event is recorded when the HelloWorld stops complying.
+func (r *HelloWorldReconciler) reportPolicyCompliance(hw *helloworldv1.HelloWorld, violations field.ErrorList) {
+	if hw.Status.Policy == nil {
+		meta.RemoveStatusCondition(&hw.Status.Conditions, helloworldv1.ConditionTypePolicyCompliant)
+		return
+	}
+
+	compliant := metav1.Condition{
+		Type:               helloworldv1.ConditionTypePolicyCompliant,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Compliant",
+		Message:            fmt.Sprintf("Complies with HelloWorldPolicy %s", strings.Join(hw.Status.Policy.Policies, ", ")),
+		ObservedGeneration: hw.Generation,
+	}
+	if len(violations) > 0 {
+		compliant.Status = metav1.ConditionFalse
+		compliant.Reason = "PolicyViolated"
+		compliant.Message = violations.ToAggregate().Error()
+		if previous := meta.FindStatusCondition(hw.Status.Conditions, compliant.Type); previous == nil ||
+			previous.Status != compliant.Status || previous.Message != compliant.Message {
+			r.Recorder.Event(hw, corev1.EventTypeWarning, "PolicyViolated", compliant.Message)
+		}
+	}
+	meta.SetStatusCondition(&hw.Status.Conditions, compliant)
+}
+
+// reportQuota sets the QuotaExceeded condition from the ways hw is over the
+// quota of its namespace, and removes it when the namespace has no quota. A
+// Warning event is recorded when the HelloWorld goes over the quota.
+func (r *HelloWorldReconciler) reportQuota(
+	hw *helloworldv1.HelloWorld, quota *helloworldv1.NamespaceQuota, overQuota field.ErrorList,
+) {
+	if quota == nil {
+		meta.RemoveStatusCondition(&hw.Status.Conditions, helloworldv1.ConditionTypeQuotaExceeded)
+		return
+	}
+
+	exceeded := metav1.Condition{
+		Type:               helloworldv1.ConditionTypeQuotaExceeded,
+		Status:             metav1.ConditionFalse,
+		Reason:             "WithinQuota",
+		Message:            fmt.Sprintf("Within the quota of namespace %s", hw.Namespace),
+		ObservedGeneration: hw.Generation,
+	}
+	if len(overQuota) > 0 {
+		exceeded.Status = metav1.ConditionTrue
+		// CheckQuota reports too many HelloWorlds first
+		exceeded.Reason = "TooManyReplicas"
+		if overQuota[0].Field == "metadata.namespace" {
+			exceeded.Reason = "TooManyHelloWorlds"
+		}
+		exceeded.Message = overQuota.ToAggregate().Error()
+		if !meta.IsStatusConditionTrue(hw.Status.Conditions, exceeded.Type) {
+			r.Recorder.Event(hw, corev1.EventTypeWarning, "QuotaExceeded", exceeded.Message)
+		}
+	}
+	meta.SetStatusCondition(&hw.Status.Conditions, exceeded)
+}
+
+// verifyContent probes the page served by the stable Service and records in
+// the ContentVerified condition whether it matches the content of one of the
+// revisions the Service should be serving. It returns when to probe again, or
+// zero when probing is disabled, in which case the condition is removed.
+func (r *HelloWorldReconciler) verifyContent(
+	ctx context.Context, hw *helloworldv1.HelloWorld, deployment *appsv1.Deployment, plan helloWorldRolloutPlan,
+) time.Duration {
+	if r.Prober == nil || r.ProbeInterval <= 0 {
+		meta.RemoveStatusCondition(&hw.Status.Conditions, helloworldv1.ConditionTypeContentVerified)
+		return 0
+	}
+	logger := log.FromContext(ctx)
+	ctx, span := tracing.Start(ctx, "HelloWorldReconciler.verifyContent",
+		tracing.NameKey.String(hw.Name), tracing.NamespaceKey.String(hw.Namespace))
+
+	verified := metav1.Condition{
+		Type:               helloworldv1.ConditionTypeContentVerified,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: hw.Generation,
+	}
+	defer func() {
+		meta.SetStatusCondition(&hw.Status.Conditions, verified)
+		tracing.End(span, verified.Reason, nil)
+	}()
+
+	if _, complete, _ := helloWorldRolloutStatus(deployment); !complete || deployment.Status.AvailableReplicas == 0 {
+		verified.Reason = "RolloutInProgress"
+		verified.Message = "The content is probed once the nginx Deployment has rolled out"
+		return r.ProbeInterval
+	}
+
+	expected := map[string]string{}
+	for _, revision := range plan.stableServiceRevisions() {
+		cm, err := getHelloWorldContentRevision(ctx, r.Client, r.apiReader(), hw, revision.Content)
+		if err != nil {
+			logger.Error(err, "Failed to get HelloWorld content revision", "revision", revision.Content)
+			verified.Reason = "RevisionNotFound"
+			verified.Message = fmt.Sprintf("Content revision %s: %v", revision.Content, err)
+			return r.ProbeInterval
+		}
+		expected[cm.Labels[helloWorldContentHashLabelKey]] = revision.Content
+	}
+
+	url := fmt.Sprintf("http://%s.%s.svc:%d/", helloWorldTrackName(hw, helloWorldStableTrack), hw.Namespace, helloWorldPort)
+	body, err := r.Prober.Probe(ctx, url)
+	if err != nil {
+		logger.Error(err, "Failed to probe HelloWorld content", "url", url)
+		verified.Status = metav1.ConditionFalse
+		verified.Reason = "ProbeFailed"
+		verified.Message = err.Error()
+		return r.ProbeInterval
+	}
+
+	logger.V(logging.DebugLevel).Info("Probed content", "url", url, "bytes", len(body))
+	if revision, ok := expected[helloWorldContentHash(string(body))]; ok {
+		verified.Status = metav1.ConditionTrue
+		verified.Reason = "ContentMatches"
+		verified.Message = fmt.Sprintf("%s serves content revision %s", url, revision)
+		return r.ProbeInterval
+	}
+
+	verified.Status = metav1.ConditionFalse
+	verified.Reason = "ContentMismatch"
+	verified.Message = fmt.Sprintf("%s serves a page that matches none of the served content revisions", url)
+	if previous := meta.FindStatusCondition(hw.Status.Conditions, verified.Type); previous == nil || previous.Reason != verified.Reason {
+		r.Recorder.Event(hw, corev1.EventTypeWarning, "ContentMismatch", verified.Message)
+	}
+
+	return r.ProbeInterval
+}
+
+// trackRollout records the progress of the nginx Deployment in the HelloWorld
+// status. A completed rollout becomes the last known-good revision. A failed
+// one is rolled back to the last known-good revision when spec.rollback.onFailure
+// is set, by marking the current generation as rolled back and requeueing so
+// the next reconcile serves the known-good revision.
+func (r *HelloWorldReconciler) trackRollout(
+	hw *helloworldv1.HelloWorld, deployment *appsv1.Deployment, revision helloworldv1.HelloWorldRevision,
+) ctrl.Result {
+	rollout, complete, failed := helloWorldRolloutStatus(deployment)
+	hw.Status.Rollout = rollout
+
+	available := metav1.Condition{
+		Type:               helloworldv1.ConditionTypeAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             "MinimumReplicasUnavailable",
+		Message:            "No nginx replicas are available",
+		ObservedGeneration: hw.Generation,
+	}
+	if rollout.AvailableReplicas > 0 {
+		available.Status = metav1.ConditionTrue
+		available.Reason = "MinimumReplicasAvailable"
+		available.Message = fmt.Sprintf("%d of %d nginx replicas are available", rollout.AvailableReplicas, rollout.Replicas)
+	}
+	meta.SetStatusCondition(&hw.Status.Conditions, available)
+
+	progressing := metav1.Condition{
+		Type:               helloworldv1.ConditionTypeProgressing,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RollingOut",
+		Message:            fmt.Sprintf("%d of %d nginx replicas are updated", rollout.UpdatedReplicas, rollout.Replicas),
+		ObservedGeneration: hw.Generation,
+	}
+
+	switch {
+	case complete:
+		progressing.Status = metav1.ConditionFalse
+		progressing.Reason = "RolloutComplete"
+		progressing.Message = "The nginx Deployment has rolled out"
+		meta.SetStatusCondition(&hw.Status.Conditions, progressing)
+
+		hw.Status.LastKnownGood = &revision
+		return ctrl.Result{}
+
+	case failed:
+		progressing.Status = metav1.ConditionFalse
+		progressing.Reason = "ProgressDeadlineExceeded"
+		progressing.Message = "The nginx Deployment exceeded its progress deadline"
+		meta.SetStatusCondition(&hw.Status.Conditions, progressing)
+
+		lastKnownGood := hw.Status.LastKnownGood
+		if hw.Spec.Rollback == nil || !hw.Spec.Rollback.OnFailure || lastKnownGood == nil || *lastKnownGood == revision {
+			return ctrl.Result{}
+		}
+
+		r.Recorder.Eventf(hw, corev1.EventTypeWarning, "RolledBack",
+			"Rollout of generation %d failed, rolling back to revision %s", hw.Generation, lastKnownGood.Content)
+		meta.SetStatusCondition(&hw.Status.Conditions, metav1.Condition{
+			Type:               helloworldv1.ConditionTypeRolledBack,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ProgressDeadlineExceeded",
+			Message:            "Serving the last known-good revision until the spec changes",
+			ObservedGeneration: hw.Generation,
+		})
+		return ctrl.Result{Requeue: true}
+
+	default:
+		meta.SetStatusCondition(&hw.Status.Conditions, progressing)
+		return ctrl.Result{}
+	}
+}
+
+// reportPlannedChanges logs the changes a dry run would make and records them
+// in the status of the HelloWorld, leaving the rest of its status alone.
+func (r *HelloWorldReconciler) reportPlannedChanges(
+	ctx context.Context, hw *helloworldv1.HelloWorld, planned []helloworldv1.PlannedChange,
+) error {
+	logger := log.FromContext(ctx)
+	for _, change := range planned {
+		logger.Info("Planned change", "action", change.Action, "kind", change.Kind, "child", change.Name, "diff", change.Diff)
+	}
+	if equality.Semantic.DeepEqual(hw.Status.PlannedChanges, planned) {
+		return nil
+	}
+
+	patch := client.MergeFrom(hw.DeepCopy())
+	hw.Status.PlannedChanges = planned
+	err := r.Status().Patch(ctx, hw, patch)
+	if err != nil {
+		logger.Error(err, "Failed to update HelloWorld planned changes")
+	}
+
+	return err
+}
+
+// resolveImage returns the HelloWorld's image pinned to a digest. A tag is
+// only resolved when the spec image changes, so a moving tag such as latest
+// does not roll out new content behind the user's back. Images outside the
+// allowed registries are rejected with a terminal error and a Warning event.
+func (r *HelloWorldReconciler) resolveImage(ctx context.Context, hw *helloworldv1.HelloWorld) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "HelloWorldReconciler.resolveImage",
+		tracing.NameKey.String(hw.Name), tracing.NamespaceKey.String(hw.Namespace))
+	var result string
+	defer func() { tracing.End(span, result, err) }()
+
+	spec := hw.Spec.Image
+	if spec == "" {
+		spec = DefaultHelloWorldImage
+	}
+
+	ref, err := image.Parse(spec)
+	if err != nil {
+		r.Recorder.Event(hw, corev1.EventTypeWarning, "InvalidImage", err.Error())
+		return "", reconcile.TerminalError(err)
+	}
+	if !ref.Allowed(r.AllowedRegistries) {
+		err = fmt.Errorf("image %s is not from an allowed registry", ref)
+		r.Recorder.Event(hw, corev1.EventTypeWarning, "ImageNotAllowed", err.Error())
+		return "", reconcile.TerminalError(err)
+	}
+	if !policy.ImageAllowed(hw.Status.Policy, ref) {
+		err = fmt.Errorf("image %s is not allowed by HelloWorldPolicy %s",
+			ref, strings.Join(hw.Status.Policy.Policies, ", "))
+		r.Recorder.Event(hw, corev1.EventTypeWarning, "ImageNotAllowed", err.Error())
+		return "", reconcile.TerminalError(err)
+	}
+
+	if hw.Status.Image == spec && hw.Status.ImageDigest != "" {
+		result = "Cached"
+		return ref.Pinned(hw.Status.ImageDigest), nil
+	}
+
+	digest, err := r.Resolver.Resolve(ctx, ref)
+	if err != nil {
+		r.Recorder.Eventf(hw, corev1.EventTypeWarning, "ImageResolutionFailed", "Failed to resolve image %s: %v", ref, err)
+		return "", err
+	}
+	r.Recorder.Eventf(hw, corev1.EventTypeNormal, "ImageResolved", "Resolved image %s to %s", ref, digest)
+	result = "Resolved"
+
+	hw.Status.Image = spec
+	hw.Status.ImageDigest = digest
+
+	return ref.Pinned(digest), nil
+}
+
+// CacheOptions returns the cache options of a manager running the HelloWorld
+// controller. ConfigMaps, Deployments, Services and Routes are only cached if
+// they are labelled as managed by the controller, instead of every such
+// object in the cluster, and managed fields are never cached.
+func CacheOptions() cache.Options {
+	managed := cache.ByObject{
+		Label: labels.SelectorFromSet(labels.Set{helloWorldManagedByLabelKey: helloWorldManagedByLabelVal}),
+	}
+
+	return cache.Options{
+		DefaultTransform: cache.TransformStripManagedFields(),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}:  managed,
+			&appsv1.Deployment{}: managed,
+			&corev1.Service{}:    managed,
+			&routev1.Route{}:     managed,
+		},
+	}
+}
+
+// helloWorldsForPolicy requests every HelloWorld, since a HelloWorldPolicy
+// may have stopped applying to namespaces it no longer selects.
+func (r *HelloWorldReconciler) helloWorldsForPolicy(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.helloWorldRequests(ctx)
+}
+
+// helloWorldsInNamespace requests the HelloWorlds of a namespace, whose
+// labels decide which HelloWorldPolicies apply to them.
+func (r *HelloWorldReconciler) helloWorldsInNamespace(ctx context.Context, ns client.Object) []reconcile.Request {
+	return r.helloWorldRequests(ctx, client.InNamespace(ns.GetName()))
+}
+
+// helloWorldsSharingQuota requests the HelloWorlds created after a
+// HelloWorld in its namespace, which may have gone over or back within the
+// quota of the namespace when it was created, scaled or deleted. Without a
+// quota, nothing is requested.
+func (r *HelloWorldReconciler) helloWorldsSharingQuota(ctx context.Context, obj client.Object) []reconcile.Request {
+	changed, ok := obj.(*helloworldv1.HelloWorld)
+	if !ok {
+		return nil
+	}
+	applied, err := policy.Effective(ctx, r.Client, changed.Namespace)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get the HelloWorldPolicies of the namespace",
+			"namespace", changed.Namespace)
+		return nil
+	}
+	if policy.Quota(applied, r.Quota) == nil {
+		return nil
+	}
+
+	list := &helloworldv1.HelloWorldList{}
+	err = r.List(ctx, list, client.InNamespace(changed.Namespace))
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list HelloWorlds")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, hw := range list.Items {
+		if hw.Name != changed.Name && policy.CreatedBefore(changed, &hw) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&hw)})
+		}
+	}
+
+	return requests
+}
+
+func (r *HelloWorldReconciler) helloWorldRequests(ctx context.Context, opts ...client.ListOption) []reconcile.Request {
+	list := &helloworldv1.HelloWorldList{}
+	err := r.List(ctx, list, opts...)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list HelloWorlds")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, hw := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&hw)})
+	}
+
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *HelloWorldReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&helloworldv1.HelloWorld{}).
+		Owns(&appsv1.Deployment{}).
+		Watches(&helloworldv1.HelloWorld{}, handler.EnqueueRequestsFromMapFunc(r.helloWorldsSharingQuota),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&helloworldv1.HelloWorldPolicy{}, handler.EnqueueRequestsFromMapFunc(r.helloWorldsForPolicy)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.helloWorldsInNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
 		Named("helloworld").
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			RateLimiter:             r.RateLimiter,
+		}).
 		Complete(r)
 }
@@ -17,17 +17,43 @@ limitations under the License.
 package controller
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	routev1 "github.com/openshift/api/route/v1"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/children"
+	"github.com/opendatahub-io/sample-component/internal/image/registrytest"
+	"github.com/opendatahub-io/sample-component/internal/logging"
+	"github.com/opendatahub-io/sample-component/internal/probe"
+	"github.com/opendatahub-io/sample-component/internal/probe/probetest"
+	"github.com/opendatahub-io/sample-component/internal/tracing"
 )
 
 var _ = Describe("HelloWorld Controller", func() {
@@ -42,7 +68,12 @@ var _ = Describe("HelloWorld Controller", func() {
 		}
 		helloworld := &helloworldv1.HelloWorld{}
 
+		var registry *registrytest.Registry
+
 		BeforeEach(func() {
+			By("starting a local registry serving the nginx image")
+			registry = startRegistry()
+
 			By("creating the custom resource for the Kind HelloWorld")
 			err := k8sClient.Get(ctx, typeNamespacedName, helloworld)
 			if err != nil && errors.IsNotFound(err) {
@@ -51,7 +82,9 @@ var _ = Describe("HelloWorld Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: helloworldv1.HelloWorldSpec{
+						Image: nginxImage(registry),
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -68,17 +101,1104 @@ var _ = Describe("HelloWorld Controller", func() {
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &HelloWorldReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
-			}
+			controllerReconciler := newReconciler(registry)
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("reporting a condition for each kind of child")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, helloworld)).To(Succeed())
+			Expect(meta.FindStatusCondition(helloworld.Status.Conditions, children.ConditionType("Deployment"))).NotTo(BeNil())
+			Expect(meta.IsStatusConditionTrue(helloworld.Status.Conditions, children.ConditionType("Service"))).To(BeTrue())
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-nginx",
+				Namespace: "default",
+			}, service)).To(Succeed())
+			Expect(service.Labels).To(HaveKeyWithValue(helloWorldNameLabelKey, resourceName))
+			Expect(service.Labels).To(HaveKeyWithValue(helloWorldManagedByLabelKey, helloWorldManagedByLabelVal))
+
+			By("recording the children in the inventory")
+			Expect(helloworld.Annotations[helloWorldInventoryAnnotationKey]).To(ContainSubstring(`"name":"` + resourceName + `-nginx"`))
+		})
+
+		It("should pin the Deployment to the resolved digest", func() {
+			controllerReconciler := newReconciler(registry)
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, helloworld)).To(Succeed())
+			Expect(helloworld.Status.ImageDigest).To(Equal("sha256:1111"))
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      resourceName + "-nginx",
+				Namespace: "default",
+			}, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal(
+				registry.Host() + "/nginxinc/nginx-unprivileged@sha256:1111"))
+
+			By("keeping the pinned digest when the tag moves")
+			registry.Push("nginxinc/nginx-unprivileged", "latest", "sha256:2222")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, helloworld)).To(Succeed())
+			Expect(helloworld.Status.ImageDigest).To(Equal("sha256:1111"))
+		})
+
+		It("should refuse images from registries that are not allowed", func() {
+			controllerReconciler := newReconciler(registry)
+			controllerReconciler.AllowedRegistries = []string{"quay.io"}
+			recorder := controllerReconciler.Recorder.(*record.FakeRecorder)
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+			Expect(recorder.Events).To(Receive(ContainSubstring("ImageNotAllowed")))
+			Expect(registry.Requests()).To(BeZero())
+		})
+	})
+
+	Context("When a rollout fails", func() {
+		const resourceName = "rollback-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		deploymentName := types.NamespacedName{
+			Name:      resourceName + "-nginx",
+			Namespace: "default",
+		}
+
+		var registry *registrytest.Registry
+		var controllerReconciler *HelloWorldReconciler
+
+		reconcileOnce := func() reconcile.Result {
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return result
+		}
+
+		// setDeploymentStatus stands in for the Deployment controller, which
+		// does not run in envtest.
+		setDeploymentStatus := func(ready bool) {
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, deployment)).To(Succeed())
+			deployment.Status = appsv1.DeploymentStatus{
+				ObservedGeneration: deployment.Generation,
+				Replicas:           1,
+				UpdatedReplicas:    1,
+			}
+			if ready {
+				deployment.Status.AvailableReplicas = 1
+				deployment.Status.ReadyReplicas = 1
+			} else {
+				deployment.Status.Conditions = []appsv1.DeploymentCondition{{
+					Type:   appsv1.DeploymentProgressing,
+					Status: corev1.ConditionFalse,
+					Reason: "ProgressDeadlineExceeded",
+				}}
+			}
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+		}
+
+		servedHTML := func() string {
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, deployment)).To(Succeed())
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name,
+				Namespace: "default",
+			}, cm)).To(Succeed())
+			return cm.Data["index.html"]
+		}
+
+		BeforeEach(func() {
+			registry = startRegistry()
+			controllerReconciler = newReconciler(registry)
+
+			Expect(k8sClient.Create(ctx, &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Message:  "known good",
+					Image:    nginxImage(registry),
+					Rollback: &helloworldv1.RollbackSpec{OnFailure: true},
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+		})
+
+		It("should roll back to the last known-good revision", func() {
+			By("completing the first rollout")
+			reconcileOnce()
+			setDeploymentStatus(true)
+			reconcileOnce()
+
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(hw.Status.LastKnownGood).NotTo(BeNil())
+			Expect(hw.Status.LastKnownGood.Content).To(Equal(hw.Status.ActiveRevision))
+			Expect(meta.IsStatusConditionFalse(hw.Status.Conditions, helloworldv1.ConditionTypeProgressing)).To(BeTrue())
+
+			By("changing the message and failing the rollout")
+			hw.Spec.Message = "broken"
+			Expect(k8sClient.Update(ctx, hw)).To(Succeed())
+			reconcileOnce()
+			Expect(servedHTML()).To(ContainSubstring("broken"))
+			setDeploymentStatus(false)
+			Expect(reconcileOnce().Requeue).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(hw.Status.Conditions, helloworldv1.ConditionTypeRolledBack)).To(BeTrue())
+
+			By("serving the known-good revision again")
+			reconcileOnce()
+			Expect(servedHTML()).To(ContainSubstring("known good"))
+			Expect(servedHTML()).NotTo(ContainSubstring("broken"))
+		})
+
+		It("should keep a bounded history of content revisions", func() {
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			hw.Spec.RevisionHistoryLimit = ptr.To[int32](1)
+			Expect(k8sClient.Update(ctx, hw)).To(Succeed())
+			reconcileOnce()
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			first := hw.Status.ActiveRevision
+
+			for _, message := range []string{"second", "third", "fourth"} {
+				Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+				hw.Spec.Message = message
+				Expect(k8sClient.Update(ctx, hw)).To(Succeed())
+				reconcileOnce()
+			}
+
+			revisions := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, revisions, client.InNamespace("default"), client.MatchingLabels{
+				"helloworld.opendatahub.io/name": resourceName,
+			})).To(Succeed())
+			Expect(revisions.Items).To(HaveLen(2))
+			for _, cm := range revisions.Items {
+				Expect(cm.Immutable).To(HaveValue(BeTrue()))
+				Expect(cm.Name).NotTo(Equal(first))
+			}
+			Expect(servedHTML()).To(ContainSubstring("fourth"))
+
+			By("pinning a revision from the history")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			pinned := hw.Status.ActiveRevision
+			hw.Spec.Message = "fifth"
+			hw.Spec.Revision = pinned
+			Expect(k8sClient.Update(ctx, hw)).To(Succeed())
+			reconcileOnce()
+			Expect(servedHTML()).To(ContainSubstring("fourth"))
+		})
+	})
+
+	Context("When messages are scheduled", func() {
+		const resourceName = "scheduled-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		start := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+		var registry *registrytest.Registry
+		var clock *clocktesting.FakePassiveClock
+		var controllerReconciler *HelloWorldReconciler
+
+		BeforeEach(func() {
+			registry = startRegistry()
+			clock = clocktesting.NewFakePassiveClock(start.Add(-time.Hour))
+			controllerReconciler = newReconciler(registry)
+			controllerReconciler.Clock = clock
+
+			Expect(k8sClient.Create(ctx, &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Message: "business as usual",
+					Image:   nginxImage(registry),
+					Schedule: []helloworldv1.ScheduleEntry{{
+						Start:   &metav1.Time{Time: start},
+						End:     &metav1.Time{Time: start.Add(2 * time.Hour)},
+						Message: "maintenance window",
+					}},
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+		})
+
+		servedHTML := func() string {
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      hw.Status.ActiveRevision,
+				Namespace: "default",
+			}, cm)).To(Succeed())
+			return cm.Data["index.html"]
+		}
+
+		It("should switch content when the window opens and closes", func() {
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+			Expect(servedHTML()).To(ContainSubstring("business as usual"))
+
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(hw.Status.Schedule.ActiveEntry).To(BeNil())
+			Expect(hw.Status.Schedule.NextTransition.Time).To(BeTemporally("==", start))
+
+			By("opening the window")
+			clock.SetTime(start)
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(2 * time.Hour))
+			Expect(servedHTML()).To(ContainSubstring("maintenance window"))
+
+			By("closing the window")
+			clock.SetTime(start.Add(2 * time.Hour))
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(servedHTML()).To(ContainSubstring("business as usual"))
+		})
+	})
+
+	Context("When running in dry-run mode", func() {
+		const resourceName = "dry-run-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		deploymentName := types.NamespacedName{
+			Name:      resourceName + "-nginx",
+			Namespace: "default",
+		}
+
+		var reconciler, dryRunReconciler *HelloWorldReconciler
+
+		BeforeEach(func() {
+			registry := startRegistry()
+			reconciler = newReconciler(registry)
+			dryRunReconciler = newReconciler(registry)
+			dryRunReconciler.DryRun = true
+
+			Expect(k8sClient.Create(ctx, &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Message: "hello",
+					Image:   nginxImage(registry),
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+		})
+
+		It("should report planned changes without making them", func() {
+			_, err := dryRunReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, deploymentName, &appsv1.Deployment{}))).To(BeTrue())
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(hw.Annotations).NotTo(HaveKey(helloWorldInventoryAnnotationKey))
+			Expect(hw.Status.ActiveRevision).To(BeEmpty())
+			Expect(hw.Status.PlannedChanges).To(ContainElements(
+				HaveField("Kind", "ConfigMap"),
+				helloworldv1.PlannedChange{Action: "Create", Kind: "Deployment", Name: deploymentName.Name},
+				helloworldv1.PlannedChange{Action: "Create", Kind: "Service", Name: deploymentName.Name},
+			))
+
+			By("diffing against the children once they exist")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(hw.Status.PlannedChanges).To(BeEmpty())
+
+			hw.Spec.Replicas = ptr.To(int32(3))
+			Expect(k8sClient.Update(ctx, hw)).To(Succeed())
+			_, err = dryRunReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hw)).To(Succeed())
+			Expect(hw.Status.PlannedChanges).To(ConsistOf(helloworldv1.PlannedChange{
+				Action: "Update", Kind: "Deployment", Name: deploymentName.Name, Diff: `{"spec":{"replicas":3}}`,
+			}))
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentName, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(1)))
+		})
+	})
+
+	Context("When child names are long or taken", func() {
+		ctx := context.Background()
+
+		var registry *registrytest.Registry
+		var reconciler *HelloWorldReconciler
+		var recorder *record.FakeRecorder
+
+		createHelloWorld := func(name string) *helloworldv1.HelloWorld {
+			hw := &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Message: "hello",
+					Image:   nginxImage(registry),
+				},
+			}
+			Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+			})
+			return hw
+		}
+
+		BeforeEach(func() {
+			registry = startRegistry()
+			reconciler = newReconciler(registry)
+			recorder = record.NewFakeRecorder(10)
+			reconciler.Recorder = recorder
+		})
+
+		It("should truncate and hash the names of children of long HelloWorlds", func() {
+			hw := createHelloWorld(strings.Repeat("a-very-long-name-", 10) + "resource")
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+			Expect(err).NotTo(HaveOccurred())
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      helloWorldTrackName(hw, helloWorldStableTrack),
+				Namespace: "default",
+			}, service)).To(Succeed())
+			Expect(len(service.Name)).To(BeNumerically("<=", 63))
+			Expect(service.Labels).To(HaveKeyWithValue(helloWorldNameLabelKey, helloWorldNameLabel(hw)))
+		})
+
+		It("should report a conflict instead of adopting objects it does not control", func() {
+			hw := createHelloWorld("conflict-resource")
+			taken := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      helloWorldTrackName(hw, helloWorldStableTrack),
+					Namespace: "default",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 80}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, taken)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, taken)).To(Succeed())
+			})
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+			var conflict *children.ConflictError
+			Expect(goerrors.As(err, &conflict)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("ChildConflict")))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hw), hw)).To(Succeed())
+			condition := meta.FindStatusCondition(hw.Status.Conditions, children.ConditionType("Service"))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("Conflict"))
+			Expect(meta.IsStatusConditionTrue(hw.Status.Conditions, helloworldv1.ConditionTypeChildConflict)).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(taken), taken)).To(Succeed())
+			Expect(taken.OwnerReferences).To(BeEmpty())
+			Expect(taken.Labels).NotTo(HaveKey(helloWorldNameLabelKey))
+
+			By("adopting it once it is annotated for adoption")
+			taken.Annotations = map[string]string{helloWorldAdoptAnnotationKey: hw.Name}
+			Expect(k8sClient.Update(ctx, taken)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(taken), taken)).To(Succeed())
+			Expect(metav1.IsControlledBy(taken, hw)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hw), hw)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(hw.Status.Conditions, helloworldv1.ConditionTypeChildConflict)).To(BeTrue())
+		})
+
+		It("should not serve a content revision it does not control", func() {
+			hw := createHelloWorld("taken-content-resource")
+			taken := helloWorldContentRevision(hw, renderHelloWorldHTML("hello"), 1)
+			taken.OwnerReferences = nil
+			taken.Data["index.html"] = "<h1>someone else's page</h1>"
+			Expect(k8sClient.Create(ctx, taken)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, taken)).To(Succeed())
+			})
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+			var conflict *children.ConflictError
+			Expect(goerrors.As(err, &conflict)).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hw), hw)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(hw.Status.Conditions, helloworldv1.ConditionTypeChildConflict)).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      helloWorldTrackName(hw, helloWorldStableTrack),
+				Namespace: "default",
+			}, &appsv1.Deployment{}))).To(BeTrue())
+
+			By("refusing to adopt it while it holds a different page")
+			taken.Annotations[helloWorldAdoptAnnotationKey] = hw.Name
+			Expect(k8sClient.Update(ctx, taken)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+			Expect(err).To(MatchError(ContainSubstring("does not hold the rendered page")))
+		})
+
+		It("should not report a content revision conflict in dry-run mode", func() {
+			hw := createHelloWorld("dry-run-conflict-resource")
+			taken := helloWorldContentRevision(hw, renderHelloWorldHTML("hello"), 1)
+			taken.OwnerReferences = nil
+			Expect(k8sClient.Create(ctx, taken)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, taken)).To(Succeed())
+			})
+
+			reconciler.DryRun = true
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+			var conflict *children.ConflictError
+			Expect(goerrors.As(err, &conflict)).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hw), hw)).To(Succeed())
+			Expect(meta.FindStatusCondition(hw.Status.Conditions, helloworldv1.ConditionTypeChildConflict)).To(BeNil())
+		})
+	})
+
+	Context("When the served content is probed", func() {
+		ctx := context.Background()
+
+		var registry *registrytest.Registry
+		var server *probetest.Server
+		var recorder *record.FakeRecorder
+
+		newProbingReconciler := func() *HelloWorldReconciler {
+			reconciler := newReconciler(registry)
+			reconciler.Recorder = recorder
+			reconciler.Prober = &probe.HTTPProber{Client: server.Client()}
+			reconciler.ProbeInterval = time.Minute
+			return reconciler
+		}
+
+		BeforeEach(func() {
+			registry = startRegistry()
+			server = probetest.New()
+			DeferCleanup(server.Close)
+			recorder = record.NewFakeRecorder(10)
+		})
+
+		It("should verify that the Service serves the rendered page", func() {
+			hw := &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "probed-resource",
+					Namespace: "default",
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Message: "hello",
+					Image:   nginxImage(registry),
+				},
+			}
+			Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+			})
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)}
+
+			By("waiting for the Deployment to roll out")
+			result, err := newProbingReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(server.Requests()).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			condition := meta.FindStatusCondition(hw.Status.Conditions, helloworldv1.ConditionTypeContentVerified)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      helloWorldTrackName(hw, helloWorldStableTrack),
+				Namespace: "default",
+			}, deployment)).To(Succeed())
+			deployment.Status = appsv1.DeploymentStatus{
+				ObservedGeneration: deployment.Generation,
+				Replicas:           1,
+				UpdatedReplicas:    1,
+				AvailableReplicas:  1,
+			}
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+
+			By("matching the served page against the content revision")
+			server.Serve(renderHelloWorldHTML("hello"))
+			result, err = newProbingReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(server.Requests()).To(ConsistOf(
+				helloWorldTrackName(hw, helloWorldStableTrack) + ".default.svc:8080/"))
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(hw.Status.Conditions, helloworldv1.ConditionTypeContentVerified)).To(BeTrue())
+
+			By("reporting a page that does not match")
+			server.Serve("<h1>stale</h1>")
+			_, err = newProbingReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("ContentMismatch")))
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			condition = meta.FindStatusCondition(hw.Status.Conditions, helloworldv1.ConditionTypeContentVerified)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("ContentMismatch"))
+
+			By("reporting a failing probe")
+			server.Fail(http.StatusServiceUnavailable)
+			_, err = newProbingReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			condition = meta.FindStatusCondition(hw.Status.Conditions, helloworldv1.ConditionTypeContentVerified)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("ProbeFailed"))
+		})
+
+		It("should only probe the pods of its own HelloWorld", func() {
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "probed-"}}
+			Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, namespace)).To(Succeed())
+			})
+			prober := &serviceProber{client: k8sClient}
+			reconciler := newProbingReconciler()
+			reconciler.Prober = prober
+
+			By("rolling out two HelloWorlds serving different messages in the namespace")
+			var hws []*helloworldv1.HelloWorld
+			for _, name := range []string{"greeter-a", "greeter-b"} {
+				hw := &helloworldv1.HelloWorld{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace.Name,
+					},
+					Spec: helloworldv1.HelloWorldSpec{
+						Message: "hello from " + name,
+						Image:   nginxImage(registry),
+					},
+				}
+				Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+				})
+				hws = append(hws, hw)
+
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+				Expect(err).NotTo(HaveOccurred())
+				deployment := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Name:      helloWorldTrackName(hw, helloWorldStableTrack),
+					Namespace: namespace.Name,
+				}, deployment)).To(Succeed())
+				deployment.Status = appsv1.DeploymentStatus{
+					ObservedGeneration: deployment.Generation,
+					Replicas:           1,
+					UpdatedReplicas:    1,
+					AvailableReplicas:  1,
+				}
+				Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+			}
+
+			By("verifying each HelloWorld against its own page on every probe")
+			for range 3 {
+				for _, hw := range hws {
+					_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hw), hw)).To(Succeed())
+					condition := meta.FindStatusCondition(hw.Status.Conditions, helloworldv1.ConditionTypeContentVerified)
+					Expect(condition).NotTo(BeNil())
+					Expect(condition.Reason).To(Equal("ContentMatches"), hw.Name)
+				}
+			}
+			Expect(prober.probes).To(HaveLen(2))
+			Expect(prober.probes).To(HaveEach(3))
+		})
+	})
+
+	Context("When reconciles are traced", func() {
+		ctx := context.Background()
+
+		var registry *registrytest.Registry
+
+		BeforeEach(func() {
+			registry = startRegistry()
+		})
+
+		It("should record a span for the reconcile and each child it reconciles", func() {
+			hw := &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "traced-resource",
+					Namespace: "default",
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Message: "hello",
+					Image:   nginxImage(registry),
+				},
+			}
+			Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+			})
+
+			exporter := tracetest.NewInMemoryExporter()
+			reconciler := newReconciler(registry)
+			reconciler.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+			Expect(err).NotTo(HaveOccurred())
+
+			spans := exporter.GetSpans()
+			root := spans[len(spans)-1]
+			Expect(root.Name).To(Equal("HelloWorldReconciler.Reconcile"))
+			Expect(root.Attributes).To(ContainElements(
+				tracing.NameKey.String("traced-resource"),
+				tracing.NamespaceKey.String("default"),
+				tracing.ResultKey.String("Done"),
+			))
+
+			results := map[string]string{}
+			for _, span := range spans[:len(spans)-1] {
+				Expect(span.Parent.SpanID()).To(Equal(root.SpanContext.SpanID()))
+				Expect(span.Attributes).To(ContainElement(tracing.NameKey.String("traced-resource")))
+				kind, result := span.Name, ""
+				for _, attr := range span.Attributes {
+					switch attr.Key {
+					case tracing.ChildKindKey:
+						kind = attr.Value.AsString()
+					case tracing.ResultKey:
+						result = attr.Value.AsString()
+					}
+				}
+				results[kind] = result
+			}
+			Expect(results).To(Equal(map[string]string{
+				"HelloWorldReconciler.resolveImage": "Resolved",
+				"ConfigMap":                         "Created",
+				"Deployment":                        "NotReady",
+				"Service":                           "Applied",
+				"children.prune":                    "Unchanged",
+			}))
+		})
+	})
+
+	Context("When a HelloWorld asks for debug logs", func() {
+		ctx := context.Background()
+
+		var registry *registrytest.Registry
+
+		BeforeEach(func() {
+			registry = startRegistry()
+		})
+
+		It("should log its reconciles at debug level and leave others at info", func() {
+			reconciler := newReconciler(registry)
+			out := &bytes.Buffer{}
+			logger := logging.New(&zap.Options{DestWriter: out})
+
+			for name, annotations := range map[string]map[string]string{
+				"noisy-resource": {helloWorldLogLevelAnnotationKey: "debug"},
+				"quiet-resource": nil,
+			} {
+				hw := &helloworldv1.HelloWorld{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name,
+						Namespace:   "default",
+						Annotations: annotations,
+					},
+					Spec: helloworldv1.HelloWorldSpec{
+						Message: "hello",
+						Image:   nginxImage(registry),
+					},
+				}
+				Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+				})
+
+				// The controller adds the name of the request to the logger
+				logCtx := log.IntoContext(ctx, logger.WithValues("name", name))
+				_, err := reconciler.Reconcile(logCtx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			levels := map[string][]string{}
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				entry := map[string]any{}
+				Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+				Expect(entry).To(HaveKeyWithValue("generation", BeNumerically("==", 1)))
+				name := entry["name"].(string)
+				levels[name] = append(levels[name], entry["level"].(string))
+			}
+			Expect(levels["noisy-resource"]).To(ContainElements("info", "debug"))
+			Expect(levels["quiet-resource"]).To(ConsistOf("info"))
+		})
+	})
+
+	Context("When the cluster serves Routes", func() {
+		ctx := context.Background()
+
+		var registry *registrytest.Registry
+		var reconciler *HelloWorldReconciler
+
+		BeforeEach(func() {
+			registry = startRegistry()
+			reconciler = newReconciler(registry)
+			reconciler.EnableRoutes = true
+		})
+
+		It("should delete the Route of a HelloWorld no longer exposed through one", func() {
+			hw := &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "routed-resource",
+					Namespace: "default",
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Image:    nginxImage(registry),
+					Exposure: helloworldv1.RouteExposureType,
+				},
+			}
+			Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+			})
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)}
+			stableName := types.NamespacedName{
+				Name:      helloWorldTrackName(hw, helloWorldStableTrack),
+				Namespace: "default",
+			}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, stableName, &routev1.Route{})).To(Succeed())
+
+			By("exposing it through its Service only")
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			hw.Spec.Exposure = helloworldv1.ServiceExposureType
+			Expect(k8sClient.Update(ctx, hw)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, stableName, &routev1.Route{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, stableName, &corev1.Service{})).To(Succeed())
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			Expect(hw.Annotations[helloWorldInventoryAnnotationKey]).NotTo(ContainSubstring(`"kind":"Route"`))
+		})
+	})
+
+	Context("When HelloWorldPolicies apply", func() {
+		ctx := context.Background()
+
+		var registry *registrytest.Registry
+		var reconciler *HelloWorldReconciler
+		var recorder *record.FakeRecorder
+
+		BeforeEach(func() {
+			registry = startRegistry()
+			reconciler = newReconciler(registry)
+			recorder = record.NewFakeRecorder(10)
+			reconciler.Recorder = recorder
+
+			policy := &helloworldv1.HelloWorldPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+				Spec: helloworldv1.HelloWorldPolicySpec{
+					MaxReplicas:      ptr.To(int32(1)),
+					RequiredLabels:   []string{"team"},
+					AllowedExposures: []helloworldv1.ExposureType{helloworldv1.ServiceExposureType},
+				},
+			}
+			Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+			})
+		})
+
+		It("should serve HelloWorlds created before a policy within its limits", func() {
+			hw := &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "policed-resource",
+					Namespace: "default",
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Image:    nginxImage(registry),
+					Replicas: ptr.To(int32(3)),
+				},
+			}
+			Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+			})
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)}
+
+			By("reporting the violations and limiting the replicas")
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("PolicyViolated")))
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			Expect(hw.Spec.Replicas).To(Equal(ptr.To(int32(3))))
+			Expect(hw.Status.Policy).NotTo(BeNil())
+			Expect(hw.Status.Policy.Policies).To(ConsistOf("tenants"))
+			Expect(hw.Status.Policy.MaxReplicas).To(Equal(ptr.To(int32(1))))
+			condition := meta.FindStatusCondition(hw.Status.Conditions, helloworldv1.ConditionTypePolicyCompliant)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(And(
+				ContainSubstring("metadata.labels[team]"),
+				ContainSubstring("spec.replicas"),
+				ContainSubstring("spec.exposure"),
+			))
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      helloWorldTrackName(hw, helloWorldStableTrack),
+				Namespace: "default",
+			}, deployment)).To(Succeed())
+			Expect(deployment.Spec.Replicas).To(Equal(ptr.To(int32(1))))
+
+			By("reporting compliance once the HelloWorld is fixed")
+			hw.Labels = map[string]string{"team": "hello"}
+			hw.Spec.Replicas = ptr.To(int32(1))
+			hw.Spec.Exposure = helloworldv1.ServiceExposureType
+			Expect(k8sClient.Update(ctx, hw)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(hw.Status.Conditions, helloworldv1.ConditionTypePolicyCompliant)).To(BeTrue())
+		})
+
+		It("should refuse to serve images the policy does not allow", func() {
+			policy := &helloworldv1.HelloWorldPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "images"},
+				Spec: helloworldv1.HelloWorldPolicySpec{
+					AllowedImages: []string{"quay.io/org"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+			})
+			hw := &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "disallowed-resource",
+					Namespace: "default",
+					Labels:    map[string]string{"team": "hello"},
+				},
+				Spec: helloworldv1.HelloWorldSpec{
+					Image: nginxImage(registry),
+				},
+			}
+			Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+			})
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hw)}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).To(MatchError(ContainSubstring("is not allowed by HelloWorldPolicy images, tenants")))
+			Expect(goerrors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, req.NamespacedName, hw)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(hw.Status.Conditions, helloworldv1.ConditionTypePolicyCompliant)).To(BeTrue())
+		})
+	})
+
+	Context("When a namespace has a quota", func() {
+		ctx := context.Background()
+
+		var registry *registrytest.Registry
+		var reconciler *HelloWorldReconciler
+
+		BeforeEach(func() {
+			registry = startRegistry()
+			reconciler = newReconciler(registry)
+			reconciler.Quota = &helloworldv1.NamespaceQuota{MaxHelloWorlds: ptr.To(int32(1))}
+		})
+
+		It("should report the HelloWorlds created after the quota was used up", func() {
+			var hws []*helloworldv1.HelloWorld
+			for _, name := range []string{"quota-a", "quota-b"} {
+				hw := &helloworldv1.HelloWorld{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+					},
+					Spec: helloworldv1.HelloWorldSpec{
+						Image: nginxImage(registry),
+					},
+				}
+				Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+				hws = append(hws, hw)
+			}
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, hws[1]))).To(Succeed())
+			})
+
+			By("reporting the HelloWorld created first as within the quota")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hws[0])})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hws[0]), hws[0])).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(hws[0].Status.Conditions, helloworldv1.ConditionTypeQuotaExceeded)).To(BeTrue())
+
+			By("reporting the HelloWorld created last as over the quota")
+			recorder := record.NewFakeRecorder(10)
+			reconciler.Recorder = recorder
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hws[1])})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("QuotaExceeded")))
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hws[1]), hws[1])).To(Succeed())
+			condition := meta.FindStatusCondition(hws[1].Status.Conditions, helloworldv1.ConditionTypeQuotaExceeded)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("TooManyHelloWorlds"))
+
+			By("reporting it within the quota once the other one is deleted")
+			Expect(k8sClient.Delete(ctx, hws[0])).To(Succeed())
+			Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKeyFromObject(hws[0]), hws[0])
+			}).Should(Satisfy(errors.IsNotFound))
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hws[1])})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hws[1]), hws[1])).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(hws[1].Status.Conditions, helloworldv1.ConditionTypeQuotaExceeded)).To(BeTrue())
+		})
+
+		It("should only requeue the HelloWorlds created after a changed one", func() {
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "quota-"}}
+			Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, namespace)).To(Succeed())
+			})
+			var hws []*helloworldv1.HelloWorld
+			for _, name := range []string{"quota-first", "quota-second"} {
+				hw := &helloworldv1.HelloWorld{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace.Name}}
+				Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+				})
+				hws = append(hws, hw)
+			}
+
+			Expect(reconciler.helloWorldsSharingQuota(ctx, hws[0])).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(hws[1])}))
+			Expect(reconciler.helloWorldsSharingQuota(ctx, hws[1])).To(BeEmpty())
+
+			By("requeueing nothing without a quota")
+			reconciler.Quota = nil
+			Expect(reconciler.helloWorldsSharingQuota(ctx, hws[0])).To(BeEmpty())
+		})
+	})
+
+	Context("When HelloWorlds are requeued", func() {
+		It("should back off per HelloWorld and share a token bucket", func() {
+			limiter := NewRateLimiter(10*time.Millisecond, 40*time.Millisecond, 1, 2)
+			first := reconcile.Request{NamespacedName: types.NamespacedName{Name: "first", Namespace: "default"}}
+			second := reconcile.Request{NamespacedName: types.NamespacedName{Name: "second", Namespace: "default"}}
+
+			By("backing off exponentially up to the maximum delay")
+			Expect(limiter.When(first)).To(Equal(10 * time.Millisecond))
+			Expect(limiter.When(first)).To(Equal(20 * time.Millisecond))
+			Expect(limiter.NumRequeues(first)).To(Equal(2))
+
+			By("delaying every HelloWorld once the burst is used up")
+			Expect(limiter.When(second)).To(BeNumerically(">", 500*time.Millisecond))
+
+			limiter.Forget(first)
+			Expect(limiter.NumRequeues(first)).To(BeZero())
 		})
 	})
 })
+
+// serviceProber probes the Service URLs of HelloWorlds the way the cluster
+// routes them, taking turns between the Deployments whose pods the Service
+// selects and answering with the page of their content revision. It counts
+// the probes of each URL.
+type serviceProber struct {
+	client client.Client
+	probes map[string]int
+}
+
+func (p *serviceProber) Probe(ctx context.Context, url string) ([]byte, error) {
+	// http://<service>.<namespace>.svc:<port>/
+	host := strings.SplitN(strings.TrimPrefix(url, "http://"), ".", 3)
+	service := &corev1.Service{}
+	if err := p.client.Get(ctx, client.ObjectKey{Namespace: host[1], Name: host[0]}, service); err != nil {
+		return nil, err
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := p.client.List(ctx, deployments, client.InNamespace(host[1])); err != nil {
+		return nil, err
+	}
+
+	var pages []string
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for _, deployment := range deployments.Items {
+		if !selector.Matches(labels.Set(deployment.Spec.Template.Labels)) {
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		key := client.ObjectKey{Namespace: host[1], Name: deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name}
+		if err := p.client.Get(ctx, key, cm); err != nil {
+			return nil, err
+		}
+		pages = append(pages, cm.Data["index.html"])
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("service %s selects no pods", host[0])
+	}
+
+	if p.probes == nil {
+		p.probes = map[string]int{}
+	}
+	page := pages[p.probes[url]%len(pages)]
+	p.probes[url]++
+	return []byte(page), nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/children"
+)
+
+// helloWorldComponentLabelKey labels the HelloWorlds deployed by the
+// HelloWorldComponent
+const helloWorldComponentLabelKey = "helloworld.opendatahub.io/component"
+
+// HelloWorldComponentReconciler reconciles the HelloWorldComponent singleton
+type HelloWorldComponentReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// ApplicationsNamespace is the namespace the HelloWorlds of the
+	// component are deployed in
+	ApplicationsNamespace string
+	// Releases are reported in the status of the component while it is
+	// managed
+	Releases []helloworldv1.ComponentRelease
+
+	// ReconcileTimeout bounds the time a single reconcile may take,
+	// unbounded if zero
+	ReconcileTimeout time.Duration
+	// DrainTimeout is how long a reconcile in flight when the manager stops may
+	// carry on, so that it finishes writing the HelloWorlds of its component
+	// before the manager releases its leader election lease. Reconciles are
+	// cancelled as soon as the manager stops if zero.
+	DrainTimeout time.Duration
+}
+
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldcomponents,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldcomponents/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldcomponents/finalizers,verbs=update
+
+// Reconcile deploys the HelloWorlds of the HelloWorldComponent in the
+// applications namespace while it is Managed, deletes them once it is
+// Removed, and reports the state of the component the way the opendatahub
+// operator expects it from every component.
+func (r *HelloWorldComponentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if r.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = drainContext(ctx, r.DrainTimeout)
+		defer cancel()
+	}
+	if r.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ReconcileTimeout)
+		defer cancel()
+	}
+
+	component := &helloworldv1.HelloWorldComponent{}
+	err := r.Get(ctx, req.NamespacedName, component)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// The HelloWorlds of a deleted component are garbage collected
+	if !component.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	logger = logger.WithValues("generation", component.Generation)
+	ctx = log.IntoContext(ctx, logger)
+	logger.Info("Reconciling HelloWorldComponent")
+
+	statusPatch := client.MergeFrom(component.DeepCopy())
+	component.Status.ObservedGeneration = component.Generation
+	provisioned := metav1.Condition{
+		Type:               helloworldv1.ConditionTypeProvisioningSucceeded,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: component.Generation,
+	}
+	ready := metav1.Condition{
+		Type:               helloworldv1.ConditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: component.Generation,
+	}
+
+	// A removed component deletes every HelloWorld it deployed
+	var deployed []helloworldv1.ComponentHelloWorld
+	if component.Spec.ManagementState != helloworldv1.RemovedState {
+		deployed = component.Spec.HelloWorlds
+	}
+	keep := sets.New[client.ObjectKey]()
+	var failures, notReady []string
+	for _, entry := range deployed {
+		key := client.ObjectKey{Namespace: r.ApplicationsNamespace, Name: entry.Name}
+		keep.Insert(key)
+		hw, message, err := r.reconcileHelloWorld(ctx, component, entry)
+		if err != nil {
+			logger.Error(err, "Failed to reconcile HelloWorld", "helloworld", key)
+			return ctrl.Result{}, err
+		}
+		if message != "" {
+			failures = append(failures, fmt.Sprintf("HelloWorld %s: %s", entry.Name, message))
+			continue
+		}
+		if ok, _ := helloWorldReady(hw); !ok {
+			notReady = append(notReady, entry.Name)
+		}
+	}
+	err = pruneOwnedHelloWorlds(ctx, r.Client, component,
+		client.MatchingLabels{helloWorldComponentLabelKey: component.Name}, keep)
+	if err != nil {
+		logger.Error(err, "Failed to delete HelloWorlds")
+		return ctrl.Result{}, err
+	}
+
+	if len(failures) > 0 {
+		provisioned.Status = metav1.ConditionFalse
+		provisioned.Reason = "ProvisioningFailed"
+		provisioned.Message = strings.Join(failures, "; ")
+		if previous := meta.FindStatusCondition(component.Status.Conditions, provisioned.Type); previous == nil ||
+			previous.Message != provisioned.Message {
+			r.Recorder.Event(component, corev1.EventTypeWarning, provisioned.Reason, provisioned.Message)
+		}
+	} else {
+		provisioned.Reason = "Provisioned"
+		provisioned.Message = fmt.Sprintf("%d HelloWorlds are deployed", len(deployed))
+	}
+	switch {
+	case component.Spec.ManagementState == helloworldv1.RemovedState:
+		ready.Reason = "Removed"
+		ready.Message = "The component is removed"
+	case len(failures) > 0:
+		ready.Reason = provisioned.Reason
+		ready.Message = provisioned.Message
+	case len(notReady) > 0:
+		ready.Reason = "HelloWorldsNotReady"
+		ready.Message = fmt.Sprintf("HelloWorlds not ready: %s", strings.Join(notReady, ", "))
+	default:
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = "AllReady"
+		ready.Message = fmt.Sprintf("%d HelloWorlds are ready", len(deployed))
+	}
+	meta.SetStatusCondition(&component.Status.Conditions, provisioned)
+	meta.SetStatusCondition(&component.Status.Conditions, ready)
+	component.Status.Phase = helloworldv1.PhaseNotReady
+	if ready.Status == metav1.ConditionTrue {
+		component.Status.Phase = helloworldv1.PhaseReady
+	}
+	component.Status.Releases = nil
+	if component.Spec.ManagementState != helloworldv1.RemovedState {
+		component.Status.Releases = r.Releases
+	}
+	if err := r.Status().Patch(ctx, component, statusPatch); err != nil {
+		logger.Error(err, "Failed to update HelloWorldComponent status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileHelloWorld creates or updates a HelloWorld of the component in
+// the applications namespace and returns it. When it cannot be deployed,
+// because the namespace does not exist, a HelloWorld the component does not
+// control already has its name, or the HelloWorld is not admitted, it
+// returns why instead.
+func (r *HelloWorldComponentReconciler) reconcileHelloWorld(
+	ctx context.Context, component *helloworldv1.HelloWorldComponent, entry helloworldv1.ComponentHelloWorld,
+) (*helloworldv1.HelloWorld, string, error) {
+	desired := &helloworldv1.HelloWorld{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      entry.Name,
+			Namespace: r.ApplicationsNamespace,
+			Labels: map[string]string{
+				helloWorldComponentLabelKey: component.Name,
+			},
+		},
+		Spec: entry.Spec,
+	}
+	if err := controllerutil.SetControllerReference(component, desired, r.Scheme); err != nil {
+		return nil, "", err
+	}
+
+	hw, err := applyOwnedHelloWorld(ctx, r.Client, component, desired)
+	var conflict *children.ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		return nil, err.Error(), nil
+	case k8serr.IsNotFound(err):
+		return nil, fmt.Sprintf("Namespace %s not found", r.ApplicationsNamespace), nil
+	case k8serr.IsForbidden(err) || k8serr.IsInvalid(err):
+		// Rejected by the API server, a HelloWorldPolicy or the quota of
+		// the namespace
+		return nil, err.Error(), nil
+	case err != nil:
+		return nil, "", err
+	}
+
+	return hw, "", nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HelloWorldComponentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&helloworldv1.HelloWorldComponent{}).
+		Owns(&helloworldv1.HelloWorld{}).
+		Named("helloworldcomponent").
+		Complete(r)
+}
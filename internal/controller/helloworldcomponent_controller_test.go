@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("HelloWorldComponent Controller", func() {
+	Context("When reconciling the HelloWorldComponent", func() {
+		const namespace = "helloworldcomponent"
+
+		ctx := context.Background()
+
+		var recorder *record.FakeRecorder
+		var component *helloworldv1.HelloWorldComponent
+
+		release := helloworldv1.ComponentRelease{
+			Name:    "sample-component",
+			Version: "v1.0.0",
+			RepoURL: "https://github.com/opendatahub-io/sample-component",
+		}
+		reconcileComponent := func() {
+			GinkgoHelper()
+			reconciler := &HelloWorldComponentReconciler{
+				Client:                k8sClient,
+				Scheme:                k8sClient.Scheme(),
+				Recorder:              recorder,
+				ApplicationsNamespace: namespace,
+				Releases:              []helloworldv1.ComponentRelease{release},
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(component)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(component), component)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+			Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).To(Succeed())
+
+			component = &helloworldv1.HelloWorldComponent{
+				ObjectMeta: metav1.ObjectMeta{Name: helloworldv1.HelloWorldComponentName},
+				Spec: helloworldv1.HelloWorldComponentSpec{
+					HelloWorlds: []helloworldv1.ComponentHelloWorld{{
+						Name: "greeter",
+						Spec: helloworldv1.HelloWorldSpec{Message: "Hello from the component"},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, component)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("deleting the component and the HelloWorlds it deployed")
+			Expect(k8sClient.Delete(ctx, component)).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &helloworldv1.HelloWorld{}, client.InNamespace(namespace),
+				client.MatchingLabels{helloWorldComponentLabelKey: helloworldv1.HelloWorldComponentName})).To(Succeed())
+		})
+
+		It("should deploy its HelloWorlds while managed and delete them once removed", func() {
+			By("deploying the HelloWorlds of the component")
+			Expect(component.Spec.ManagementState).To(Equal(helloworldv1.ManagedState))
+			reconcileComponent()
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "greeter"}, hw)).To(Succeed())
+			Expect(metav1.IsControlledBy(hw, component)).To(BeTrue())
+			Expect(hw.Spec.Message).To(Equal("Hello from the component"))
+
+			Expect(component.Status.ObservedGeneration).To(Equal(component.Generation))
+			Expect(component.Status.Releases).To(ConsistOf(release))
+			Expect(component.Status.Phase).To(Equal(helloworldv1.PhaseNotReady))
+			Expect(meta.IsStatusConditionTrue(component.Status.Conditions,
+				helloworldv1.ConditionTypeProvisioningSucceeded)).To(BeTrue())
+			ready := meta.FindStatusCondition(component.Status.Conditions, helloworldv1.ConditionTypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("HelloWorldsNotReady"))
+
+			By("reporting the component ready once its HelloWorlds are available")
+			hw.Status.ObservedGeneration = hw.Generation
+			meta.SetStatusCondition(&hw.Status.Conditions, metav1.Condition{
+				Type:   helloworldv1.ConditionTypeAvailable,
+				Status: metav1.ConditionTrue,
+				Reason: "MinimumReplicasAvailable",
+			})
+			Expect(k8sClient.Status().Update(ctx, hw)).To(Succeed())
+			reconcileComponent()
+			Expect(component.Status.Phase).To(Equal(helloworldv1.PhaseReady))
+			Expect(meta.IsStatusConditionTrue(component.Status.Conditions, helloworldv1.ConditionTypeReady)).To(BeTrue())
+
+			By("deleting the HelloWorlds once the component is removed")
+			component.Spec.ManagementState = helloworldv1.RemovedState
+			Expect(k8sClient.Update(ctx, component)).To(Succeed())
+			reconcileComponent()
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(hw), &helloworldv1.HelloWorld{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(component.Status.Phase).To(Equal(helloworldv1.PhaseNotReady))
+			Expect(component.Status.Releases).To(BeEmpty())
+			ready = meta.FindStatusCondition(component.Status.Conditions, helloworldv1.ConditionTypeReady)
+			Expect(ready.Reason).To(Equal("Removed"))
+		})
+
+		It("should report the HelloWorlds it cannot deploy", func() {
+			By("creating a HelloWorld the component does not control")
+			taken := &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "greeter",
+					Namespace: namespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, taken)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, taken)).To(Succeed())
+			})
+
+			reconcileComponent()
+			provisioned := meta.FindStatusCondition(component.Status.Conditions,
+				helloworldv1.ConditionTypeProvisioningSucceeded)
+			Expect(provisioned).NotTo(BeNil())
+			Expect(provisioned.Status).To(Equal(metav1.ConditionFalse))
+			Expect(provisioned.Message).To(ContainSubstring("not controlled by its parent"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("ProvisioningFailed")))
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(taken), taken)).To(Succeed())
+			Expect(metav1.GetControllerOf(taken)).To(BeNil())
+
+			By("reporting a HelloWorld the API server rejects")
+			component.Spec.HelloWorlds = append(component.Spec.HelloWorlds,
+				helloworldv1.ComponentHelloWorld{Name: "Not_A_Name"})
+			Expect(k8sClient.Update(ctx, component)).To(Succeed())
+			reconcileComponent()
+			provisioned = meta.FindStatusCondition(component.Status.Conditions,
+				helloworldv1.ConditionTypeProvisioningSucceeded)
+			Expect(provisioned.Status).To(Equal(metav1.ConditionFalse))
+			Expect(provisioned.Message).To(ContainSubstring("HelloWorld Not_A_Name: "))
+			Expect(provisioned.Message).To(ContainSubstring("metadata.name: Invalid value"))
+			Expect(component.Status.Phase).To(Equal(helloworldv1.PhaseNotReady))
+		})
+
+		It("should only accept a component named default-helloworldcomponent", func() {
+			other := &helloworldv1.HelloWorldComponent{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+			err := k8sClient.Create(ctx, other)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/names"
+)
+
+// helloWorldSetLabelKey labels the HelloWorlds generated by a HelloWorldSet with its name
+const helloWorldSetLabelKey = "helloworld.opendatahub.io/set"
+
+// Built-in parameters of HelloWorldTemplates
+const (
+	helloWorldNameParameter      = "metadata.name"
+	helloWorldNamespaceParameter = "metadata.namespace"
+)
+
+// helloWorldSetLabel returns the value of the set label of the HelloWorlds
+// generated by a HelloWorldSet.
+func helloWorldSetLabel(set *helloworldv1.HelloWorldSet) string {
+	return names.LabelValue(set.Name)
+}
+
+// helloWorldSetInstances returns the HelloWorlds a set generates, in
+// namespace and name order: its instances and one for each of the selected
+// namespaces, each with the parameters of the set and of the entries naming it.
+func helloWorldSetInstances(set *helloworldv1.HelloWorldSet, namespaces []string) []helloworldv1.HelloWorldSetInstance {
+	byKey := map[string]*helloworldv1.HelloWorldSetInstance{}
+	add := func(namespace, name string, parameters map[string]string) {
+		if name == "" {
+			name = set.Name
+		}
+		key := namespace + "/" + name
+		instance, ok := byKey[key]
+		if !ok {
+			instance = &helloworldv1.HelloWorldSetInstance{
+				Namespace:  namespace,
+				Name:       name,
+				Parameters: maps.Clone(set.Spec.Parameters),
+			}
+			if instance.Parameters == nil {
+				instance.Parameters = map[string]string{}
+			}
+			byKey[key] = instance
+		}
+		maps.Copy(instance.Parameters, parameters)
+	}
+
+	for _, namespace := range namespaces {
+		add(namespace, "", nil)
+	}
+	for _, instance := range set.Spec.Instances {
+		add(instance.Namespace, instance.Name, instance.Parameters)
+	}
+
+	instances := make([]helloworldv1.HelloWorldSetInstance, 0, len(byKey))
+	for _, instance := range byKey {
+		instances = append(instances, *instance)
+	}
+	slices.SortFunc(instances, func(a, b helloworldv1.HelloWorldSetInstance) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	return instances
+}
+
+// renderHelloWorldSetInstance returns the HelloWorld generated from a
+// template for an instance of a set. Every $(parameter) in the labels,
+// annotations and string fields of the template is replaced with its value,
+// and parameters the instance is missing, or the template does not declare,
+// are returned as errors.
+func renderHelloWorldSetInstance(
+	set *helloworldv1.HelloWorldSet, tmpl *helloworldv1.HelloWorldTemplate, instance helloworldv1.HelloWorldSetInstance,
+) (*helloworldv1.HelloWorld, field.ErrorList) {
+	var errs field.ErrorList
+	path := field.NewPath("parameters")
+
+	values := map[string]string{
+		helloWorldNameParameter:      instance.Name,
+		helloWorldNamespaceParameter: instance.Namespace,
+	}
+	declared := make([]string, 0, len(tmpl.Spec.Parameters))
+	for _, parameter := range tmpl.Spec.Parameters {
+		declared = append(declared, parameter.Name)
+		switch value, ok := instance.Parameters[parameter.Name]; {
+		case ok:
+			values[parameter.Name] = value
+		case parameter.Default != nil:
+			values[parameter.Name] = *parameter.Default
+		default:
+			errs = append(errs, field.Required(path.Key(parameter.Name),
+				"required by HelloWorldTemplate "+tmpl.Name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(instance.Parameters)) {
+		if !slices.Contains(declared, name) {
+			errs = append(errs, field.NotSupported(path, name, declared))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	hw := &helloworldv1.HelloWorld{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        instance.Name,
+			Namespace:   instance.Namespace,
+			Labels:      expandParameterValues(tmpl.Spec.Template.Labels, values),
+			Annotations: expandParameterValues(tmpl.Spec.Template.Annotations, values),
+		},
+	}
+	if hw.Labels == nil {
+		hw.Labels = map[string]string{}
+	}
+	hw.Labels[helloWorldSetLabelKey] = helloWorldSetLabel(set)
+
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&tmpl.Spec.Template.Spec)
+	if err == nil {
+		expandParameters(spec, values)
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &hw.Spec)
+	}
+	if err != nil {
+		return nil, field.ErrorList{field.InternalError(field.NewPath("spec"), err)}
+	}
+
+	return hw, nil
+}
+
+// expandParameters replaces the parameters referenced in the strings of an
+// unstructured object, in place.
+func expandParameters(obj any, values map[string]string) any {
+	switch obj := obj.(type) {
+	case string:
+		return expandParameter(obj, values)
+	case map[string]any:
+		for key, value := range obj {
+			obj[key] = expandParameters(value, values)
+		}
+	case []any:
+		for i, value := range obj {
+			obj[i] = expandParameters(value, values)
+		}
+	}
+	return obj
+}
+
+// expandParameterValues returns a copy of m with the parameters referenced
+// in its values replaced.
+func expandParameterValues(m map[string]string, values map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	expanded := make(map[string]string, len(m))
+	for key, value := range m {
+		expanded[key] = expandParameter(value, values)
+	}
+	return expanded
+}
+
+// expandParameter replaces every $(name) of a parameter in s with its value.
+// References to anything else are left as written, and values are not
+// expanded themselves.
+func expandParameter(s string, values map[string]string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "$(")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], ')')
+		if end < 0 {
+			break
+		}
+		end += start
+		value, ok := values[s[start+2:end]]
+		if !ok {
+			value = s[start : end+1]
+		}
+		b.WriteString(s[:start])
+		b.WriteString(value)
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+
+	return b.String()
+}
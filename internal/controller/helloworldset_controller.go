@@ -0,0 +1,322 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/children"
+)
+
+// HelloWorldSetReconciler reconciles a HelloWorldSet object
+type HelloWorldSetReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// ReconcileTimeout bounds the time a single reconcile may take,
+	// unbounded if zero
+	ReconcileTimeout time.Duration
+	// DrainTimeout is how long a reconcile in flight when the manager stops may
+	// carry on, so that it finishes writing the HelloWorlds of its set before
+	// the manager releases its leader election lease. Reconciles are cancelled
+	// as soon as the manager stops if zero.
+	DrainTimeout time.Duration
+}
+
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldsets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldsets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldtemplates,verbs=get;list;watch
+
+// Reconcile generates the HelloWorlds of the requested HelloWorldSet from its
+// template, deletes those it no longer generates, and reports their
+// readiness in its status.
+func (r *HelloWorldSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if r.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = drainContext(ctx, r.DrainTimeout)
+		defer cancel()
+	}
+	if r.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ReconcileTimeout)
+		defer cancel()
+	}
+
+	set := &helloworldv1.HelloWorldSet{}
+	err := r.Get(ctx, req.NamespacedName, set)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// The HelloWorlds of a deleted set are garbage collected
+	if !set.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	logger = logger.WithValues("generation", set.Generation)
+	ctx = log.IntoContext(ctx, logger)
+	logger.Info("Reconciling HelloWorldSet")
+
+	statusPatch := client.MergeFrom(set.DeepCopy())
+	set.Status.ObservedGeneration = set.Generation
+	ready := metav1.Condition{
+		Type:               helloworldv1.ConditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: set.Generation,
+	}
+
+	// Without a template or a valid namespace selector, the HelloWorlds
+	// generated so far are left as they are
+	tmpl := &helloworldv1.HelloWorldTemplate{}
+	err = r.Get(ctx, client.ObjectKey{Name: set.Spec.TemplateName}, tmpl)
+	if k8serr.IsNotFound(err) {
+		ready.Reason = "TemplateNotFound"
+		ready.Message = fmt.Sprintf("HelloWorldTemplate %s not found", set.Spec.TemplateName)
+		return ctrl.Result{}, r.reportFailure(ctx, set, statusPatch, ready)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get HelloWorldTemplate")
+		return ctrl.Result{}, err
+	}
+	var namespaces []string
+	if set.Spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(set.Spec.NamespaceSelector)
+		if err != nil {
+			ready.Reason = "InvalidNamespaceSelector"
+			ready.Message = fmt.Sprintf("Invalid namespace selector: %v", err)
+			return ctrl.Result{}, r.reportFailure(ctx, set, statusPatch, ready)
+		}
+		namespaces, err = r.selectedNamespaces(ctx, selector)
+		if err != nil {
+			logger.Error(err, "Failed to list namespaces")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Generate every instance, then delete the HelloWorlds of instances
+	// that are gone. HelloWorlds that cannot be generated are kept as they
+	// were last generated.
+	instances := helloWorldSetInstances(set, namespaces)
+	previous := set.Status.HelloWorlds
+	set.Status.Instances = int32(len(instances))
+	set.Status.ReadyInstances = 0
+	set.Status.HelloWorlds = make([]helloworldv1.HelloWorldSetInstanceStatus, 0, len(instances))
+	keep := sets.New[client.ObjectKey]()
+	failed := 0
+	for _, instance := range instances {
+		key := client.ObjectKey{Namespace: instance.Namespace, Name: instance.Name}
+		keep.Insert(key)
+		status, generated, err := r.reconcileInstance(ctx, set, tmpl, instance)
+		if err != nil {
+			logger.Error(err, "Failed to reconcile HelloWorld", "helloworld", key)
+			return ctrl.Result{}, err
+		}
+		set.Status.HelloWorlds = append(set.Status.HelloWorlds, status)
+		if status.Ready {
+			set.Status.ReadyInstances++
+		}
+		if !generated {
+			failed++
+			if !slices.Contains(previous, status) {
+				r.Recorder.Eventf(set, corev1.EventTypeWarning, "GenerationFailed", "HelloWorld %s/%s: %s",
+					status.Namespace, status.Name, status.Message)
+			}
+		}
+	}
+	err = pruneOwnedHelloWorlds(ctx, r.Client, set, client.MatchingLabels{helloWorldSetLabelKey: helloWorldSetLabel(set)}, keep)
+	if err != nil {
+		logger.Error(err, "Failed to delete HelloWorlds")
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case failed > 0:
+		ready.Reason = "GenerationFailed"
+		ready.Message = fmt.Sprintf("%d of %d HelloWorlds could not be generated", failed, len(instances))
+	case set.Status.ReadyInstances < set.Status.Instances:
+		ready.Reason = "InstancesNotReady"
+		ready.Message = fmt.Sprintf("%d of %d HelloWorlds are ready", set.Status.ReadyInstances, set.Status.Instances)
+	default:
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = "AllReady"
+		ready.Message = fmt.Sprintf("%d of %d HelloWorlds are ready", set.Status.ReadyInstances, set.Status.Instances)
+	}
+	meta.SetStatusCondition(&set.Status.Conditions, ready)
+	if err := r.Status().Patch(ctx, set, statusPatch); err != nil {
+		logger.Error(err, "Failed to update HelloWorldSet status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reportFailure sets the Ready condition of a set whose HelloWorlds cannot
+// be generated at all, recording a Warning event when its reason changes,
+// and patches the status.
+func (r *HelloWorldSetReconciler) reportFailure(
+	ctx context.Context, set *helloworldv1.HelloWorldSet, statusPatch client.Patch, ready metav1.Condition,
+) error {
+	if previous := meta.FindStatusCondition(set.Status.Conditions, ready.Type); previous == nil || previous.Reason != ready.Reason {
+		r.Recorder.Event(set, corev1.EventTypeWarning, ready.Reason, ready.Message)
+	}
+	meta.SetStatusCondition(&set.Status.Conditions, ready)
+	if err := r.Status().Patch(ctx, set, statusPatch); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update HelloWorldSet status")
+		return err
+	}
+
+	return nil
+}
+
+// selectedNamespaces returns the names of the namespaces matching selector,
+// leaving out those being deleted.
+func (r *HelloWorldSetReconciler) selectedNamespaces(ctx context.Context, selector labels.Selector) ([]string, error) {
+	list := &corev1.NamespaceList{}
+	if err := r.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		if ns.DeletionTimestamp.IsZero() {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	return namespaces, nil
+}
+
+// reconcileInstance creates or updates the HelloWorld of an instance of a
+// set and returns its state. It reports whether the HelloWorld was
+// generated, which it is not when the parameters of the instance are
+// invalid, its namespace does not exist, a HelloWorld the set does not
+// control already has its name, or the HelloWorld is not admitted.
+func (r *HelloWorldSetReconciler) reconcileInstance(
+	ctx context.Context, set *helloworldv1.HelloWorldSet, tmpl *helloworldv1.HelloWorldTemplate,
+	instance helloworldv1.HelloWorldSetInstance,
+) (helloworldv1.HelloWorldSetInstanceStatus, bool, error) {
+	status := helloworldv1.HelloWorldSetInstanceStatus{
+		Namespace: instance.Namespace,
+		Name:      instance.Name,
+	}
+
+	desired, errs := renderHelloWorldSetInstance(set, tmpl, instance)
+	if len(errs) > 0 {
+		status.Message = errs.ToAggregate().Error()
+		return status, false, nil
+	}
+	if err := controllerutil.SetControllerReference(set, desired, r.Scheme); err != nil {
+		return status, false, err
+	}
+
+	hw, err := applyOwnedHelloWorld(ctx, r.Client, set, desired)
+	var conflict *children.ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		status.Message = err.Error()
+		return status, false, nil
+	case k8serr.IsNotFound(err):
+		status.Message = fmt.Sprintf("Namespace %s not found", instance.Namespace)
+		return status, false, nil
+	case k8serr.IsForbidden(err) || k8serr.IsInvalid(err):
+		// Rejected by the API server, a HelloWorldPolicy or the quota of
+		// the namespace
+		status.Message = err.Error()
+		return status, false, nil
+	case err != nil:
+		return status, false, err
+	}
+	status.Ready, status.Message = helloWorldReady(hw)
+
+	return status, true, nil
+}
+
+// helloWorldSetsForTemplate requests the HelloWorldSets generating
+// HelloWorlds from a template.
+func (r *HelloWorldSetReconciler) helloWorldSetsForTemplate(ctx context.Context, tmpl client.Object) []reconcile.Request {
+	return r.helloWorldSetRequests(ctx, func(set *helloworldv1.HelloWorldSet) bool {
+		return set.Spec.TemplateName == tmpl.GetName()
+	})
+}
+
+// helloWorldSetsForNamespace requests the HelloWorldSets that may generate a
+// HelloWorld in a namespace that was created, deleted or relabelled: those
+// selecting namespaces, and those listing an instance in it.
+func (r *HelloWorldSetReconciler) helloWorldSetsForNamespace(ctx context.Context, ns client.Object) []reconcile.Request {
+	return r.helloWorldSetRequests(ctx, func(set *helloworldv1.HelloWorldSet) bool {
+		return set.Spec.NamespaceSelector != nil ||
+			slices.ContainsFunc(set.Spec.Instances, func(instance helloworldv1.HelloWorldSetInstance) bool {
+				return instance.Namespace == ns.GetName()
+			})
+	})
+}
+
+func (r *HelloWorldSetReconciler) helloWorldSetRequests(
+	ctx context.Context, matches func(*helloworldv1.HelloWorldSet) bool,
+) []reconcile.Request {
+	list := &helloworldv1.HelloWorldSetList{}
+	err := r.List(ctx, list)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list HelloWorldSets")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, set := range list.Items {
+		if matches(&set) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&set)})
+		}
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HelloWorldSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&helloworldv1.HelloWorldSet{}).
+		Owns(&helloworldv1.HelloWorld{}).
+		Watches(&helloworldv1.HelloWorldTemplate{}, handler.EnqueueRequestsFromMapFunc(r.helloWorldSetsForTemplate)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.helloWorldSetsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Named("helloworldset").
+		Complete(r)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("HelloWorldSet Controller", func() {
+	Context("When reconciling a HelloWorldSet", func() {
+		ctx := context.Background()
+
+		var recorder *record.FakeRecorder
+		var tmpl *helloworldv1.HelloWorldTemplate
+		var set *helloworldv1.HelloWorldSet
+
+		newReconciler := func() *HelloWorldSetReconciler {
+			return &HelloWorldSetReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+		}
+		reconcileSet := func() {
+			GinkgoHelper()
+			_, err := newReconciler().Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(set)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(set), set)).To(Succeed())
+		}
+		generated := func(namespace, name string) *helloworldv1.HelloWorld {
+			GinkgoHelper()
+			hw := &helloworldv1.HelloWorld{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, hw)).To(Succeed())
+			return hw
+		}
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+
+			By("creating a namespace selected by the set")
+			ns := &corev1.Namespace{}
+			err := k8sClient.Get(ctx, client.ObjectKey{Name: "helloworldset-selected"}, ns)
+			if errors.IsNotFound(err) {
+				ns = &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "helloworldset-selected",
+						Labels: map[string]string{"greeters": "true"},
+					},
+				}
+				err = k8sClient.Create(ctx, ns)
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			By("creating the template and the set")
+			tmpl = &helloworldv1.HelloWorldTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "greeter"},
+				Spec: helloworldv1.HelloWorldTemplateSpec{
+					Parameters: []helloworldv1.TemplateParameter{
+						{Name: "environment"},
+						{Name: "greeting", Default: ptr.To("Hello")},
+					},
+					Template: helloworldv1.HelloWorldInstanceTemplate{
+						Labels: map[string]string{"environment": "$(environment)"},
+						Spec: helloworldv1.HelloWorldSpec{
+							Message:  "$(greeting) from $(metadata.namespace)",
+							Exposure: helloworldv1.ServiceExposureType,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tmpl)).To(Succeed())
+			set = &helloworldv1.HelloWorldSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "greeters"},
+				Spec: helloworldv1.HelloWorldSetSpec{
+					TemplateName: "greeter",
+					Parameters:   map[string]string{"environment": "development"},
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"greeters": "true"},
+					},
+					Instances: []helloworldv1.HelloWorldSetInstance{{
+						Namespace:  "default",
+						Name:       "greeter-production",
+						Parameters: map[string]string{"environment": "production", "greeting": "Hi"},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, set)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("deleting the set, its template and the HelloWorlds it generated")
+			Expect(k8sClient.Delete(ctx, set)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, tmpl)).To(Succeed())
+			for _, namespace := range []string{"default", "helloworldset-selected"} {
+				Expect(k8sClient.DeleteAllOf(ctx, &helloworldv1.HelloWorld{}, client.InNamespace(namespace),
+					client.MatchingLabels{helloWorldSetLabelKey: "greeters"})).To(Succeed())
+			}
+		})
+
+		It("should generate the HelloWorlds of the set and aggregate their readiness", func() {
+			By("generating a HelloWorld for the instance and the selected namespace")
+			reconcileSet()
+			hw := generated("default", "greeter-production")
+			Expect(metav1.IsControlledBy(hw, set)).To(BeTrue())
+			Expect(hw.Labels).To(HaveKeyWithValue("environment", "production"))
+			Expect(hw.Spec.Message).To(Equal("Hi from default"))
+			hw = generated("helloworldset-selected", "greeters")
+			Expect(hw.Labels).To(HaveKeyWithValue("environment", "development"))
+			Expect(hw.Spec.Message).To(Equal("Hello from helloworldset-selected"))
+
+			Expect(set.Status.Instances).To(Equal(int32(2)))
+			Expect(set.Status.ReadyInstances).To(BeZero())
+			ready := meta.FindStatusCondition(set.Status.Conditions, helloworldv1.ConditionTypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("InstancesNotReady"))
+
+			By("reporting the set ready once its HelloWorlds are available")
+			for _, key := range []client.ObjectKey{
+				{Namespace: "default", Name: "greeter-production"},
+				{Namespace: "helloworldset-selected", Name: "greeters"},
+			} {
+				hw := generated(key.Namespace, key.Name)
+				hw.Status.ObservedGeneration = hw.Generation
+				meta.SetStatusCondition(&hw.Status.Conditions, metav1.Condition{
+					Type:   helloworldv1.ConditionTypeAvailable,
+					Status: metav1.ConditionTrue,
+					Reason: "MinimumReplicasAvailable",
+				})
+				Expect(k8sClient.Status().Update(ctx, hw)).To(Succeed())
+			}
+			reconcileSet()
+			Expect(set.Status.ReadyInstances).To(Equal(int32(2)))
+			Expect(meta.IsStatusConditionTrue(set.Status.Conditions, helloworldv1.ConditionTypeReady)).To(BeTrue())
+
+			By("updating the HelloWorlds when the template changes")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(tmpl), tmpl)).To(Succeed())
+			tmpl.Spec.Template.Spec.Message = "$(greeting) again from $(metadata.namespace)"
+			Expect(k8sClient.Update(ctx, tmpl)).To(Succeed())
+			reconcileSet()
+			Expect(generated("default", "greeter-production").Spec.Message).To(Equal("Hi again from default"))
+			Expect(set.Status.ReadyInstances).To(BeZero())
+
+			By("deleting the HelloWorld of an instance that was removed")
+			set.Spec.Instances = nil
+			Expect(k8sClient.Update(ctx, set)).To(Succeed())
+			reconcileSet()
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "greeter-production"}, &helloworldv1.HelloWorld{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(set.Status.Instances).To(Equal(int32(1)))
+		})
+
+		It("should report the HelloWorlds that cannot be generated", func() {
+			By("creating a HelloWorld the set does not control")
+			taken := &helloworldv1.HelloWorld{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "greeter-production",
+					Namespace: "default",
+				},
+			}
+			Expect(k8sClient.Create(ctx, taken)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, taken)).To(Succeed())
+			})
+
+			By("leaving out a required parameter of the selected namespace")
+			set.Spec.Parameters = nil
+			Expect(k8sClient.Update(ctx, set)).To(Succeed())
+
+			reconcileSet()
+			Expect(set.Status.HelloWorlds).To(ConsistOf(
+				helloworldv1.HelloWorldSetInstanceStatus{
+					Namespace: "default",
+					Name:      "greeter-production",
+					Message:   "HelloWorld greeter-production already exists and is not controlled by its parent",
+				},
+				helloworldv1.HelloWorldSetInstanceStatus{
+					Namespace: "helloworldset-selected",
+					Name:      "greeters",
+					Message:   "parameters[environment]: Required value: required by HelloWorldTemplate greeter",
+				},
+			))
+			ready := meta.FindStatusCondition(set.Status.Conditions, helloworldv1.ConditionTypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal("GenerationFailed"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("GenerationFailed")))
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(taken), taken)).To(Succeed())
+			Expect(metav1.GetControllerOf(taken)).To(BeNil())
+
+			By("reporting a HelloWorld the API server rejects")
+			set.Spec.Parameters = map[string]string{"environment": "not a label value"}
+			Expect(k8sClient.Update(ctx, set)).To(Succeed())
+			reconcileSet()
+			Expect(set.Status.HelloWorlds).To(ContainElement(And(
+				HaveField("Namespace", "helloworldset-selected"),
+				HaveField("Message", ContainSubstring("metadata.labels: Invalid value")),
+			)))
+			ready = meta.FindStatusCondition(set.Status.Conditions, helloworldv1.ConditionTypeReady)
+			Expect(ready.Reason).To(Equal("GenerationFailed"))
+			Expect(ready.Message).To(Equal("2 of 2 HelloWorlds could not be generated"))
+
+			By("reporting a missing template")
+			set.Spec.TemplateName = "missing"
+			Expect(k8sClient.Update(ctx, set)).To(Succeed())
+			reconcileSet()
+			ready = meta.FindStatusCondition(set.Status.Conditions, helloworldv1.ConditionTypeReady)
+			Expect(ready.Reason).To(Equal("TemplateNotFound"))
+		})
+	})
+})
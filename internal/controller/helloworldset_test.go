@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("HelloWorldSet templates", func() {
+	set := &helloworldv1.HelloWorldSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "greeters"},
+		Spec: helloworldv1.HelloWorldSetSpec{
+			TemplateName: "greeter",
+			Parameters:   map[string]string{"environment": "development"},
+		},
+	}
+	tmpl := &helloworldv1.HelloWorldTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "greeter"},
+		Spec: helloworldv1.HelloWorldTemplateSpec{
+			Parameters: []helloworldv1.TemplateParameter{
+				{Name: "environment"},
+				{Name: "greeting", Default: ptr.To("Hello")},
+			},
+			Template: helloworldv1.HelloWorldInstanceTemplate{
+				Labels: map[string]string{"environment": "$(environment)"},
+				Spec: helloworldv1.HelloWorldSpec{
+					Message: "$(greeting) from $(metadata.namespace), costs $(USD)",
+					Schedule: []helloworldv1.ScheduleEntry{
+						{Cron: "0 9 * * *", Message: "$(greeting) $(metadata.name)"},
+					},
+					Replicas: ptr.To(int32(2)),
+				},
+			},
+		},
+	}
+
+	It("lists the instances and the selected namespaces, merging their parameters", func() {
+		set := set.DeepCopy()
+		set.Spec.Instances = []helloworldv1.HelloWorldSetInstance{
+			{Namespace: "prod", Name: "hello", Parameters: map[string]string{"environment": "production"}},
+			{Namespace: "team-a", Parameters: map[string]string{"greeting": "Hi"}},
+			{Namespace: "team-a", Parameters: map[string]string{"greeting": "Hey"}},
+		}
+
+		Expect(helloWorldSetInstances(set, []string{"team-b", "team-a"})).To(Equal([]helloworldv1.HelloWorldSetInstance{
+			{Namespace: "prod", Name: "hello", Parameters: map[string]string{"environment": "production"}},
+			{Namespace: "team-a", Name: "greeters", Parameters: map[string]string{"environment": "development", "greeting": "Hey"}},
+			{Namespace: "team-b", Name: "greeters", Parameters: map[string]string{"environment": "development"}},
+		}))
+	})
+
+	It("fills in the parameters of the template", func() {
+		instance := helloworldv1.HelloWorldSetInstance{
+			Namespace:  "team-a",
+			Name:       "greeters",
+			Parameters: map[string]string{"environment": "staging"},
+		}
+
+		hw, errs := renderHelloWorldSetInstance(set, tmpl, instance)
+		Expect(errs).To(BeEmpty())
+		Expect(hw.Namespace).To(Equal("team-a"))
+		Expect(hw.Name).To(Equal("greeters"))
+		Expect(hw.Labels).To(Equal(map[string]string{
+			"environment":         "staging",
+			helloWorldSetLabelKey: "greeters",
+		}))
+		Expect(hw.Spec.Message).To(Equal("Hello from team-a, costs $(USD)"))
+		Expect(hw.Spec.Schedule[0].Message).To(Equal("Hello greeters"))
+		Expect(hw.Spec.Replicas).To(Equal(ptr.To(int32(2))))
+
+		By("leaving the template untouched")
+		Expect(tmpl.Spec.Template.Labels["environment"]).To(Equal("$(environment)"))
+		Expect(tmpl.Spec.Template.Spec.Message).To(HavePrefix("$(greeting)"))
+	})
+
+	It("reports missing and unknown parameters", func() {
+		instance := helloworldv1.HelloWorldSetInstance{
+			Namespace:  "team-a",
+			Name:       "greeters",
+			Parameters: map[string]string{"greting": "Hi"},
+		}
+
+		_, errs := renderHelloWorldSetInstance(set, tmpl, instance)
+		Expect(errs.ToAggregate().Error()).To(Equal(`[parameters[environment]: Required value: required by HelloWorldTemplate greeter, ` +
+			`parameters: Unsupported value: "greting": supported values: "environment", "greeting"]`))
+	})
+})
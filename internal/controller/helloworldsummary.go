@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+// helloWorldSummaryMaxFailing is the most failing HelloWorlds a
+// HelloWorldSummary lists
+const helloWorldSummaryMaxFailing = 50
+
+// summarizeHelloWorlds rolls up the state of the HelloWorlds of a namespace.
+func summarizeHelloWorlds(hws []helloworldv1.HelloWorld) helloworldv1.HelloWorldSummaryStatus {
+	sorted := make([]*helloworldv1.HelloWorld, 0, len(hws))
+	for i := range hws {
+		sorted = append(sorted, &hws[i])
+	}
+	slices.SortFunc(sorted, func(a, b *helloworldv1.HelloWorld) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	status := helloworldv1.HelloWorldSummaryStatus{
+		Total: int32(len(hws)),
+	}
+	for _, hw := range sorted {
+		if ready, _ := helloWorldReady(hw); ready {
+			status.Ready++
+		}
+		condition := helloWorldDegradedCondition(hw)
+		if condition == nil {
+			continue
+		}
+		status.Degraded++
+		if len(status.Failing) < helloWorldSummaryMaxFailing {
+			status.Failing = append(status.Failing, helloworldv1.FailingHelloWorld{
+				Name:      hw.Name,
+				Condition: condition.Type,
+				Reason:    condition.Reason,
+				Message:   condition.Message,
+			})
+		}
+	}
+
+	return status
+}
+
+// helloWorldDegradedCondition returns the first condition of a HelloWorld
+// reporting it as degraded, or nil if it is not.
+func helloWorldDegradedCondition(hw *helloworldv1.HelloWorld) *metav1.Condition {
+	for i, condition := range hw.Status.Conditions {
+		if helloWorldConditionDegrades(condition) {
+			return &hw.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// helloWorldConditionDegrades reports whether a condition of a HelloWorld
+// reports a failure, rather than a rollout in progress or a HelloWorld
+// scaled to zero.
+func helloWorldConditionDegrades(condition metav1.Condition) bool {
+	switch condition.Type {
+	case helloworldv1.ConditionTypeRolledBack,
+		helloworldv1.ConditionTypeChildConflict,
+		helloworldv1.ConditionTypeQuotaExceeded:
+		return condition.Status == metav1.ConditionTrue
+	case helloworldv1.ConditionTypePolicyCompliant:
+		return condition.Status == metav1.ConditionFalse
+	case helloworldv1.ConditionTypeContentVerified:
+		return condition.Status == metav1.ConditionFalse && condition.Reason != "RolloutInProgress"
+	case helloworldv1.ConditionTypeProgressing:
+		return condition.Reason == "ProgressDeadlineExceeded"
+	case helloworldv1.ConditionTypeAvailable:
+		return false
+	}
+
+	// The conditions of the children of each kind
+	return condition.Reason == "ApplyFailed"
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+// HelloWorldSummaryReconciler maintains the HelloWorldSummary of every
+// namespace holding HelloWorlds. Each reconcile lists the HelloWorlds of its
+// namespace from the cache, so it costs O(n) for a namespace of n
+// HelloWorlds. A change to a HelloWorld enqueues the summary of its namespace
+// CoalesceDelay later, and the changes made meanwhile, such as the update of
+// every HelloWorld on a resync, are rolled up by a single reconcile instead
+// of one each, which would cost O(n²).
+type HelloWorldSummaryReconciler struct {
+	client.Client
+
+	// CoalesceDelay is how long the summary of a namespace waits after a
+	// change to one of its HelloWorlds before it is reconciled. Summaries
+	// are reconciled as soon as a HelloWorld changes if zero.
+	CoalesceDelay time.Duration
+
+	// ReconcileTimeout bounds the time a single reconcile may take,
+	// unbounded if zero
+	ReconcileTimeout time.Duration
+	// DrainTimeout is how long a reconcile in flight when the manager stops may
+	// carry on, so that it finishes writing the status of its summary before the
+	// manager releases its leader election lease. Reconciles are cancelled as
+	// soon as the manager stops if zero.
+	DrainTimeout time.Duration
+}
+
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldsummaries,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldsummaries/status,verbs=get;update;patch
+
+// Reconcile rolls up the HelloWorlds of the namespace of the requested
+// summary into its status, creating the summary if the namespace holds
+// HelloWorlds and deleting it if it holds none.
+func (r *HelloWorldSummaryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if r.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = drainContext(ctx, r.DrainTimeout)
+		defer cancel()
+	}
+	if r.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ReconcileTimeout)
+		defer cancel()
+	}
+
+	// Only the summary named helloworlds is maintained, and the API server
+	// does not accept any other
+	if req.Name != helloworldv1.HelloWorldSummaryName {
+		return ctrl.Result{}, nil
+	}
+
+	list := &helloworldv1.HelloWorldList{}
+	err := r.List(ctx, list, client.InNamespace(req.Namespace))
+	if err != nil {
+		logger.Error(err, "Failed to list HelloWorlds")
+		return ctrl.Result{}, err
+	}
+
+	summary := &helloworldv1.HelloWorldSummary{}
+	err = r.Get(ctx, req.NamespacedName, summary)
+	if client.IgnoreNotFound(err) != nil {
+		logger.Error(err, "Failed to get HelloWorldSummary")
+		return ctrl.Result{}, err
+	}
+	found := err == nil
+
+	if len(list.Items) == 0 {
+		if found {
+			if err := r.Delete(ctx, summary); client.IgnoreNotFound(err) != nil {
+				logger.Error(err, "Failed to delete HelloWorldSummary")
+				return ctrl.Result{}, err
+			}
+			logger.Info("Deleted HelloWorldSummary")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !found {
+		summary = &helloworldv1.HelloWorldSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      req.Name,
+				Namespace: req.Namespace,
+				Labels: map[string]string{
+					helloWorldManagedByLabelKey: helloWorldManagedByLabelVal,
+				},
+			},
+		}
+		err := r.Create(ctx, summary)
+		if k8serr.IsAlreadyExists(err) {
+			// Created since the cache was last updated, and requeued by its creation
+			return ctrl.Result{}, nil
+		}
+		if err != nil {
+			logger.Error(err, "Failed to create HelloWorldSummary")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Created HelloWorldSummary")
+	}
+
+	status := summarizeHelloWorlds(list.Items)
+	if equality.Semantic.DeepEqual(summary.Status, status) {
+		return ctrl.Result{}, nil
+	}
+	statusPatch := client.MergeFrom(summary.DeepCopy())
+	summary.Status = status
+	if err := r.Status().Patch(ctx, summary, statusPatch); err != nil {
+		logger.Error(err, "Failed to update HelloWorldSummary status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// helloWorldSummaryOf requests the summary of the namespace of a HelloWorld.
+func helloWorldSummaryOf(_ context.Context, hw client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{
+		Namespace: hw.GetNamespace(),
+		Name:      helloworldv1.HelloWorldSummaryName,
+	}}}
+}
+
+// enqueueSummary returns the handler of HelloWorld events, which requests the
+// summary of the namespace of the HelloWorld CoalesceDelay later. The
+// workqueue holds a single request for the summary until then.
+func (r *HelloWorldSummaryReconciler) enqueueSummary() handler.EventHandler {
+	enqueue := func(ctx context.Context, hw client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		for _, req := range helloWorldSummaryOf(ctx, hw) {
+			q.AddAfter(req, r.CoalesceDelay)
+		}
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.ObjectNew, q)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, q)
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, q)
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HelloWorldSummaryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&helloworldv1.HelloWorldSummary{}).
+		Watches(&helloworldv1.HelloWorld{}, r.enqueueSummary()).
+		Named("helloworldsummary").
+		Complete(r)
+}
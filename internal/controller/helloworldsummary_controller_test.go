@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("HelloWorldSummary Controller", func() {
+	Context("When summarizing the HelloWorlds of a namespace", func() {
+		const namespace = "helloworldsummary"
+
+		ctx := context.Background()
+
+		summaryKey := client.ObjectKey{Namespace: namespace, Name: helloworldv1.HelloWorldSummaryName}
+		reconcileSummary := func() {
+			GinkgoHelper()
+			reconciler := &HelloWorldSummaryReconciler{Client: k8sClient}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: summaryKey})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+			Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).To(Succeed())
+		})
+
+		It("should maintain the summary while the namespace holds HelloWorlds", func() {
+			By("creating a summary for the HelloWorlds of the namespace")
+			var hws []*helloworldv1.HelloWorld
+			for _, name := range []string{"serving", "failing"} {
+				hw := &helloworldv1.HelloWorld{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+					},
+				}
+				Expect(k8sClient.Create(ctx, hw)).To(Succeed())
+				hws = append(hws, hw)
+			}
+			DeferCleanup(func() {
+				for _, hw := range hws {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, hw))).To(Succeed())
+				}
+			})
+			reconcileSummary()
+			summary := &helloworldv1.HelloWorldSummary{}
+			Expect(k8sClient.Get(ctx, summaryKey, summary)).To(Succeed())
+			Expect(summary.Status).To(Equal(helloworldv1.HelloWorldSummaryStatus{Total: 2}))
+
+			By("rolling up the state of the HelloWorlds")
+			hws[0].Status.ObservedGeneration = hws[0].Generation
+			meta.SetStatusCondition(&hws[0].Status.Conditions, metav1.Condition{
+				Type:   helloworldv1.ConditionTypeAvailable,
+				Status: metav1.ConditionTrue,
+				Reason: "MinimumReplicasAvailable",
+			})
+			Expect(k8sClient.Status().Update(ctx, hws[0])).To(Succeed())
+			meta.SetStatusCondition(&hws[1].Status.Conditions, metav1.Condition{
+				Type:    helloworldv1.ConditionTypeChildConflict,
+				Status:  metav1.ConditionTrue,
+				Reason:  "NotControlled",
+				Message: "Service failing-nginx already exists and is not controlled by its parent",
+			})
+			Expect(k8sClient.Status().Update(ctx, hws[1])).To(Succeed())
+			reconcileSummary()
+			Expect(k8sClient.Get(ctx, summaryKey, summary)).To(Succeed())
+			Expect(summary.Status).To(Equal(helloworldv1.HelloWorldSummaryStatus{
+				Total:    2,
+				Ready:    1,
+				Degraded: 1,
+				Failing: []helloworldv1.FailingHelloWorld{{
+					Name:      "failing",
+					Condition: helloworldv1.ConditionTypeChildConflict,
+					Reason:    "NotControlled",
+					Message:   "Service failing-nginx already exists and is not controlled by its parent",
+				}},
+			}))
+
+			By("deleting the summary once the namespace holds no HelloWorld")
+			for _, hw := range hws {
+				Expect(k8sClient.Delete(ctx, hw)).To(Succeed())
+			}
+			reconcileSummary()
+			err := k8sClient.Get(ctx, summaryKey, summary)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should only accept summaries named helloworlds", func() {
+			summary := &helloworldv1.HelloWorldSummary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other",
+					Namespace: namespace,
+				},
+			}
+			err := k8sClient.Create(ctx, summary)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+		})
+	})
+
+	Context("When the HelloWorlds of a namespace change in a burst", func() {
+		ctx := context.Background()
+
+		It("should reconcile the summary of each namespace once", func() {
+			queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			DeferCleanup(queue.ShutDown)
+			reconciler := &HelloWorldSummaryReconciler{CoalesceDelay: 100 * time.Millisecond}
+			handler := reconciler.enqueueSummary()
+
+			var mu sync.Mutex
+			reconciles := map[string]int{}
+			go func() {
+				for {
+					req, shutdown := queue.Get()
+					if shutdown {
+						return
+					}
+					mu.Lock()
+					reconciles[req.Namespace]++
+					mu.Unlock()
+					queue.Done(req)
+				}
+			}()
+			reconciled := func() map[string]int {
+				mu.Lock()
+				defer mu.Unlock()
+				return maps.Clone(reconciles)
+			}
+
+			By("updating every HelloWorld of two namespaces, as a resync does")
+			for i := range 1000 {
+				hw := &helloworldv1.HelloWorld{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("hw-%d", i),
+						Namespace: []string{"first", "second"}[i%2],
+					},
+				}
+				handler.Update(ctx, event.UpdateEvent{ObjectOld: hw, ObjectNew: hw}, queue)
+				if i%100 == 0 {
+					// Let the worker catch up, as it would between events
+					time.Sleep(time.Millisecond)
+				}
+			}
+
+			By("listing each namespace once the delay is over")
+			expected := map[string]int{"first": 1, "second": 1}
+			Eventually(reconciled).Should(Equal(expected))
+			Consistently(reconciled).WithTimeout(200 * time.Millisecond).Should(Equal(expected))
+		})
+	})
+})
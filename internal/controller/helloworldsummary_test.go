@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/children"
+)
+
+var _ = Describe("HelloWorld summaries", func() {
+	newHelloWorld := func(name string, conditions ...metav1.Condition) helloworldv1.HelloWorld {
+		return helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+			Status: helloworldv1.HelloWorldStatus{
+				ObservedGeneration: 1,
+				Conditions:         conditions,
+			},
+		}
+	}
+	condition := func(conditionType string, status metav1.ConditionStatus, reason string) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status, Reason: reason, Message: reason + " message"}
+	}
+	available := condition(helloworldv1.ConditionTypeAvailable, metav1.ConditionTrue, "MinimumReplicasAvailable")
+
+	It("counts the ready and degraded HelloWorlds, and lists the failing ones in name order", func() {
+		stale := newHelloWorld("stale", available)
+		stale.Generation = 2
+
+		status := summarizeHelloWorlds([]helloworldv1.HelloWorld{
+			newHelloWorld("rolled-back", available,
+				condition(helloworldv1.ConditionTypeProgressing, metav1.ConditionFalse, "ProgressDeadlineExceeded"),
+				condition(helloworldv1.ConditionTypeRolledBack, metav1.ConditionTrue, "ProgressDeadlineExceeded")),
+			newHelloWorld("ready", available,
+				condition(helloworldv1.ConditionTypeProgressing, metav1.ConditionFalse, "RolloutComplete"),
+				condition(helloworldv1.ConditionTypeQuotaExceeded, metav1.ConditionFalse, "WithinQuota")),
+			newHelloWorld("conflicting",
+				condition(children.ConditionType("Service"), metav1.ConditionFalse, "ApplyFailed")),
+			newHelloWorld("rolling-out",
+				condition(helloworldv1.ConditionTypeAvailable, metav1.ConditionFalse, "MinimumReplicasUnavailable"),
+				condition(helloworldv1.ConditionTypeContentVerified, metav1.ConditionUnknown, "RolloutInProgress")),
+			stale,
+		})
+
+		Expect(status).To(Equal(helloworldv1.HelloWorldSummaryStatus{
+			Total:    5,
+			Ready:    2,
+			Degraded: 2,
+			Failing: []helloworldv1.FailingHelloWorld{
+				{Name: "conflicting", Condition: "ServiceReady", Reason: "ApplyFailed", Message: "ApplyFailed message"},
+				{Name: "rolled-back", Condition: helloworldv1.ConditionTypeProgressing,
+					Reason: "ProgressDeadlineExceeded", Message: "ProgressDeadlineExceeded message"},
+			},
+		}))
+	})
+
+	It("lists a bounded number of failing HelloWorlds", func() {
+		var hws []helloworldv1.HelloWorld
+		for i := range helloWorldSummaryMaxFailing + 10 {
+			hws = append(hws, newHelloWorld(fmt.Sprintf("hw-%03d", i),
+				condition(helloworldv1.ConditionTypePolicyCompliant, metav1.ConditionFalse, "PolicyViolated")))
+		}
+
+		status := summarizeHelloWorlds(hws)
+		Expect(status.Degraded).To(Equal(int32(helloWorldSummaryMaxFailing + 10)))
+		Expect(status.Failing).To(HaveLen(helloWorldSummaryMaxFailing))
+		Expect(status.Failing[0].Name).To(Equal("hw-000"))
+	})
+})
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/image"
+	"github.com/opendatahub-io/sample-component/internal/image/registrytest"
+)
+
+// The load test measures how many HelloWorlds a controller manager running
+// in envtest reconciles per second. It only runs when HELLOWORLD_LOAD_TEST_SIZES
+// lists the numbers of HelloWorlds to create, such as 1000,10000, and
+// HELLOWORLD_LOAD_TEST_WORKERS optionally sets MaxConcurrentReconciles:
+//
+//	HELLOWORLD_LOAD_TEST_SIZES=1000,10000 make test-load
+var _ = Describe("HelloWorld Controller under load", Label("load"), func() {
+	It("should reconcile every HelloWorld", func() {
+		sizes := os.Getenv("HELLOWORLD_LOAD_TEST_SIZES")
+		if sizes == "" {
+			Skip("HELLOWORLD_LOAD_TEST_SIZES is not set")
+		}
+		workers := 16
+		if w := os.Getenv("HELLOWORLD_LOAD_TEST_WORKERS"); w != "" {
+			var err error
+			workers, err = strconv.Atoi(w)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		for _, size := range strings.Split(sizes, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(size))
+			Expect(err).NotTo(HaveOccurred())
+
+			By(fmt.Sprintf("reconciling %d HelloWorlds with %d workers", n, workers))
+			elapsed := runLoadTest(n, workers)
+			throughput := float64(n) / elapsed.Seconds()
+			AddReportEntry(fmt.Sprintf("%d HelloWorlds", n), fmt.Sprintf("%s, %.1f HelloWorlds/s", elapsed, throughput))
+			GinkgoWriter.Printf("%d HelloWorlds reconciled by %d workers in %s: %.1f/s\n", n, workers, elapsed, throughput)
+		}
+	})
+})
+
+// runLoadTest creates n HelloWorlds in a new namespace, starts a manager
+// reconciling them and returns the time it took until all of them reported
+// a status for their generation.
+func runLoadTest(n int, workers int) time.Duration {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Neither the test nor the manager should be throttled by the client
+	loadCfg := rest.CopyConfig(cfg)
+	loadCfg.QPS = -1
+	cli, err := client.New(loadCfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+
+	registry := registrytest.New()
+	defer registry.Close()
+	registry.Push("nginxinc/nginx-unprivileged", "latest", "sha256:1111")
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: fmt.Sprintf("load-%d-", n)}}
+	Expect(cli.Create(ctx, namespace)).To(Succeed())
+
+	By("creating the HelloWorlds")
+	var wg sync.WaitGroup
+	names := make(chan int)
+	for range 32 {
+		wg.Add(1)
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			for i := range names {
+				Expect(cli.Create(ctx, &helloworldv1.HelloWorld{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("load-%d", i),
+						Namespace: namespace.Name,
+					},
+					Spec: helloworldv1.HelloWorldSpec{
+						Message: fmt.Sprintf("Hello from %d", i),
+						Image:   nginxImage(registry),
+					},
+				})).To(Succeed())
+			}
+		}()
+	}
+	for i := range n {
+		names <- i
+	}
+	close(names)
+	wg.Wait()
+
+	By("starting the controller")
+	mgr, err := ctrl.NewManager(loadCfg, ctrl.Options{
+		Scheme:  scheme.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		// Every size runs its own controller named helloworld
+		Controller: config.Controller{SkipNameValidation: ptr.To(true)},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect((&HelloWorldReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: &record.FakeRecorder{},
+		Resolver: &image.RegistryResolver{Client: registry.Client()},
+		Clock:    clock.RealClock{},
+
+		MaxConcurrentReconciles: workers,
+		RateLimiter:             NewRateLimiter(5*time.Millisecond, time.Minute, float64(n), n),
+	}).SetupWithManager(mgr)).To(Succeed())
+
+	start := time.Now()
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+
+	Eventually(func(g Gomega) int {
+		list := &helloworldv1.HelloWorldList{}
+		g.Expect(cli.List(ctx, list, client.InNamespace(namespace.Name))).To(Succeed())
+		reconciled := 0
+		for _, hw := range list.Items {
+			if hw.Status.ObservedGeneration == hw.Generation {
+				reconciled++
+			}
+		}
+		return reconciled
+	}).WithTimeout(time.Hour).WithPolling(time.Second).Should(Equal(n))
+
+	return time.Since(start)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/children"
+)
+
+// applyOwnedHelloWorld creates a HelloWorld generated by an owner, such as a
+// HelloWorldSet, or brings the one the owner controls in line with it, and
+// returns the HelloWorld as stored. Labels and annotations the owner does
+// not set are left alone. It returns a children.ConflictError if a
+// HelloWorld the owner does not control has the name, and a NotFound error
+// if the namespace does not exist.
+func applyOwnedHelloWorld(
+	ctx context.Context, c client.Client, owner client.Object, desired *helloworldv1.HelloWorld,
+) (*helloworldv1.HelloWorld, error) {
+	hw := &helloworldv1.HelloWorld{}
+	err := c.Get(ctx, client.ObjectKeyFromObject(desired), hw)
+	if k8serr.IsNotFound(err) {
+		if err := c.Create(ctx, desired); err != nil {
+			return nil, err
+		}
+		log.FromContext(ctx).Info("Created HelloWorld", "helloworld", client.ObjectKeyFromObject(desired))
+		return desired, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := children.CheckController("HelloWorld", hw, owner, ""); err != nil {
+		return nil, err
+	}
+	original := hw.DeepCopy()
+	if hw.Labels == nil {
+		hw.Labels = map[string]string{}
+	}
+	maps.Copy(hw.Labels, desired.Labels)
+	if len(desired.Annotations) > 0 && hw.Annotations == nil {
+		hw.Annotations = map[string]string{}
+	}
+	maps.Copy(hw.Annotations, desired.Annotations)
+	hw.Spec = desired.Spec
+	if equality.Semantic.DeepEqual(original, hw) {
+		return hw, nil
+	}
+	if err := c.Patch(ctx, hw, client.MergeFrom(original)); err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("Updated HelloWorld", "helloworld", client.ObjectKeyFromObject(hw))
+
+	return hw, nil
+}
+
+// pruneOwnedHelloWorlds deletes the HelloWorlds matching labels that owner
+// controls, except those to keep.
+func pruneOwnedHelloWorlds(
+	ctx context.Context, c client.Client, owner client.Object, labels client.MatchingLabels, keep sets.Set[client.ObjectKey],
+) error {
+	list := &helloworldv1.HelloWorldList{}
+	err := c.List(ctx, list, labels)
+	if err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		hw := &list.Items[i]
+		if keep.Has(client.ObjectKeyFromObject(hw)) || !metav1.IsControlledBy(hw, owner) {
+			continue
+		}
+		if err := c.Delete(ctx, hw); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Deleted HelloWorld", "helloworld", client.ObjectKeyFromObject(hw))
+	}
+
+	return nil
+}
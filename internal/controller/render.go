@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/image"
+)
+
+// RenderOptions configures RenderHelloWorld.
+type RenderOptions struct {
+	// Now is the time the schedule is evaluated at
+	Now time.Time
+	// Resolver, if set, pins the image to a digest as the controller does.
+	// Without it the image is rendered as written in the spec.
+	Resolver image.Resolver
+	// EnableRoutes renders the Route of HelloWorlds exposed through one, as on
+	// clusters serving route.openshift.io
+	EnableRoutes bool
+}
+
+// RenderHelloWorld returns the children the controller would create for a
+// HelloWorld, without a cluster: the content revision of the page it serves,
+// unless spec.revision pins one, followed by its Deployments, Services and
+// Route in the order they are applied. Rollout and rollback state is taken
+// from the HelloWorld's status, if it has any.
+func RenderHelloWorld(
+	ctx context.Context, scheme *runtime.Scheme, hw *helloworldv1.HelloWorld, opts RenderOptions,
+) ([]client.Object, error) {
+	spec := hw.Spec.Image
+	if spec == "" {
+		spec = DefaultHelloWorldImage
+	}
+	ref, err := image.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	revision := helloworldv1.HelloWorldRevision{
+		Image: ref.String(),
+	}
+	if opts.Resolver != nil {
+		digest, err := opts.Resolver.Resolve(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve image %s: %w", ref, err)
+		}
+		revision.Image = ref.Pinned(digest)
+	}
+
+	message, _, _, err := scheduledMessage(hw, opts.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []client.Object
+	if hw.Spec.Revision != "" {
+		revision.Content = hw.Spec.Revision
+	} else {
+		cm := helloWorldContentRevision(hw, renderHelloWorldHTML(message), 1)
+		revision.Content = cm.Name
+		objs = append(objs, cm)
+	}
+
+	routes := helloWorldRoutes(hw, opts.EnableRoutes)
+	plan := planHelloWorldRollout(hw, revision, routes)
+	state := &helloWorldState{hw: hw, plan: plan, routeAPI: opts.EnableRoutes, routes: routes}
+	children, err := helloWorldChildren.Render(ctx, scheme, hw, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(objs, children...), nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/image"
+	"github.com/opendatahub-io/sample-component/internal/image/registrytest"
+)
+
+var _ = Describe("Rendering HelloWorlds", func() {
+	ctx := context.Background()
+	now := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	var renderScheme *runtime.Scheme
+
+	BeforeEach(func() {
+		renderScheme = runtime.NewScheme()
+		Expect(helloworldv1.AddToScheme(renderScheme)).To(Succeed())
+	})
+
+	newHelloWorld := func() *helloworldv1.HelloWorld {
+		return &helloworldv1.HelloWorld{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "helloworld.opendatahub.io/v1", Kind: "HelloWorld"},
+			ObjectMeta: metav1.ObjectMeta{Name: "hw", Namespace: "default"},
+			Spec:       helloworldv1.HelloWorldSpec{Message: "hello"},
+		}
+	}
+
+	kinds := func(objs []client.Object) []string {
+		var kinds []string
+		for _, obj := range objs {
+			kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+		}
+		return kinds
+	}
+
+	It("renders the children in the order they are applied", func() {
+		objs, err := RenderHelloWorld(ctx, renderScheme, newHelloWorld(), RenderOptions{Now: now, EnableRoutes: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(objs)).To(HaveExactElements(
+			"ConfigMap/hw-html-"+helloWorldContentHash(renderHelloWorldHTML("hello")),
+			"Deployment/hw-nginx",
+			"Service/hw-nginx",
+			"Route/hw-nginx",
+		))
+
+		deployment := objs[1].(*appsv1.Deployment)
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("docker.io/nginxinc/nginx-unprivileged:latest"))
+		Expect(deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal(objs[0].GetName()))
+		Expect(deployment.Labels).To(HaveKeyWithValue(helloWorldNameLabelKey, "hw"))
+		Expect(deployment.OwnerReferences).To(HaveLen(1))
+		Expect(objs[3].(*routev1.Route).Spec.To.Name).To(Equal("hw-nginx"))
+	})
+
+	It("renders the canary track of a rollout in progress", func() {
+		hw := newHelloWorld()
+		hw.Spec.Strategy = &helloworldv1.StrategySpec{
+			Type:   helloworldv1.CanaryStrategyType,
+			Canary: &helloworldv1.CanaryStrategy{Weight: 20},
+		}
+		hw.Status.Strategy = &helloworldv1.StrategyStatus{
+			Phase:          helloworldv1.StrategyPhaseStable,
+			StableRevision: "hw-html-old",
+		}
+
+		objs, err := RenderHelloWorld(ctx, renderScheme, hw, RenderOptions{Now: now})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(objs)).To(ContainElements("Deployment/hw-nginx-canary", "Service/hw-nginx-canary"))
+		Expect(kinds(objs)).NotTo(ContainElement(HavePrefix("Route/")))
+		Expect(objs[1].(*appsv1.Deployment).Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("hw-html-old"))
+	})
+
+	It("renders the scheduled message and a pinned revision", func() {
+		hw := newHelloWorld()
+		hw.Spec.Schedule = []helloworldv1.ScheduleEntry{{
+			Start:   &metav1.Time{Time: now.Add(-time.Hour)},
+			End:     &metav1.Time{Time: now.Add(time.Hour)},
+			Message: "maintenance",
+		}}
+		objs, err := RenderHelloWorld(ctx, renderScheme, hw, RenderOptions{Now: now})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs[0].(*corev1.ConfigMap).Data["index.html"]).To(ContainSubstring("maintenance"))
+
+		hw.Spec.Revision = "hw-html-pinned"
+		objs, err = RenderHelloWorld(ctx, renderScheme, hw, RenderOptions{Now: now})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(objs)).To(HaveExactElements("Deployment/hw-nginx", "Service/hw-nginx"))
+		Expect(objs[0].(*appsv1.Deployment).Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("hw-html-pinned"))
+	})
+
+	It("pins the image to a digest with a resolver", func() {
+		registry := registrytest.New()
+		defer registry.Close()
+		registry.Push("org/nginx", "v1", "sha256:1111")
+
+		hw := newHelloWorld()
+		hw.Spec.Image = registry.Host() + "/org/nginx:v1"
+		objs, err := RenderHelloWorld(ctx, renderScheme, hw, RenderOptions{
+			Now:      now,
+			Resolver: &image.RegistryResolver{Client: registry.Client()},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs[1].(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Image).To(Equal(
+			registry.Host() + "/org/nginx@sha256:1111"))
+	})
+})
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+// scheduleWindow is a window of a schedule entry relative to a point in time:
+// the window that contains it, if any, and the next one that opens after it.
+type scheduleWindow struct {
+	open      bool
+	end       time.Time
+	nextStart time.Time
+}
+
+// window evaluates a schedule entry at now.
+func window(entry helloworldv1.ScheduleEntry, now time.Time) (scheduleWindow, error) {
+	if entry.Cron == "" {
+		if entry.Start == nil || entry.End == nil {
+			return scheduleWindow{}, fmt.Errorf("schedule entry needs either cron or start and end")
+		}
+		w := scheduleWindow{end: entry.End.Time}
+		if now.Before(entry.Start.Time) {
+			w.nextStart = entry.Start.Time
+		} else {
+			w.open = now.Before(entry.End.Time)
+		}
+		return w, nil
+	}
+
+	if entry.Duration == nil || entry.Duration.Duration <= 0 {
+		return scheduleWindow{}, fmt.Errorf("schedule entry %q needs a positive duration", entry.Cron)
+	}
+	schedule, err := cron.ParseStandard(entry.Cron)
+	if err != nil {
+		return scheduleWindow{}, fmt.Errorf("invalid cron expression %q: %w", entry.Cron, err)
+	}
+
+	// The window is open if the schedule fired within the last duration.
+	// Next returns the zero time for schedules that never fire, such as on
+	// February 30th.
+	start := schedule.Next(now.Add(-entry.Duration.Duration))
+	if start.IsZero() {
+		return scheduleWindow{}, fmt.Errorf("cron expression %q never fires", entry.Cron)
+	}
+	w := scheduleWindow{nextStart: schedule.Next(now)}
+	if !start.After(now) {
+		w.open = true
+		w.end = start.Add(entry.Duration.Duration)
+	}
+	return w, nil
+}
+
+// scheduledMessage returns the message to serve at now, the index of the
+// schedule entry it comes from and when the served message is next expected
+// to change. The index is nil when no window is open and Message is served,
+// and the transition is nil when the schedule never changes again.
+func scheduledMessage(hw *helloworldv1.HelloWorld, now time.Time) (string, *int32, *time.Time, error) {
+	message := hw.Spec.Message
+	var active *int32
+	var next *time.Time
+
+	earliest := func(t time.Time) {
+		if !t.IsZero() && t.After(now) && (next == nil || t.Before(*next)) {
+			next = ptr.To(t)
+		}
+	}
+
+	for i, entry := range hw.Spec.Schedule {
+		w, err := window(entry, now)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		// Entries after the active one cannot change the message before it
+		// closes, and closing it is already a transition
+		if active != nil {
+			continue
+		}
+		earliest(w.nextStart)
+		if w.open {
+			active = ptr.To(int32(i))
+			message = entry.Message
+			earliest(w.end)
+		}
+	}
+
+	return message, active, next, nil
+}
+
+// scheduleStatus returns the schedule status to report, nil without a schedule.
+func scheduleStatus(hw *helloworldv1.HelloWorld, active *int32, next *time.Time) *helloworldv1.ScheduleStatus {
+	if len(hw.Spec.Schedule) == 0 {
+		return nil
+	}
+
+	status := &helloworldv1.ScheduleStatus{ActiveEntry: active}
+	if next != nil {
+		status.NextTransition = ptr.To(metav1.NewTime(*next))
+	}
+
+	return status
+}
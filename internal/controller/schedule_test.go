@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("HelloWorld schedules", func() {
+	// A Monday
+	monday := time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC)
+
+	newHelloWorld := func(schedule ...helloworldv1.ScheduleEntry) *helloworldv1.HelloWorld {
+		return &helloworldv1.HelloWorld{
+			Spec: helloworldv1.HelloWorldSpec{
+				Message:  "default",
+				Schedule: schedule,
+			},
+		}
+	}
+	officeHours := helloworldv1.ScheduleEntry{
+		Cron:     "0 9 * * 1-5",
+		Duration: &metav1.Duration{Duration: 8 * time.Hour},
+		Message:  "open",
+	}
+
+	DescribeTable("serving a cron window",
+		func(now time.Time, message string, active bool, next time.Time) {
+			m, a, n, err := scheduledMessage(newHelloWorld(officeHours), now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m).To(Equal(message))
+			Expect(a != nil).To(Equal(active))
+			Expect(*n).To(BeTemporally("==", next))
+		},
+		Entry("before the window", monday.Add(8*time.Hour), "default", false, monday.Add(9*time.Hour)),
+		Entry("when the window opens", monday.Add(9*time.Hour), "open", true, monday.Add(17*time.Hour)),
+		Entry("inside the window", monday.Add(12*time.Hour), "open", true, monday.Add(17*time.Hour)),
+		Entry("when the window closes", monday.Add(17*time.Hour), "default", false, monday.Add(33*time.Hour)),
+		Entry("over the weekend", monday.Add(-24*time.Hour), "default", false, monday.Add(9*time.Hour)),
+	)
+
+	It("prefers earlier entries and wakes up when one of them opens", func() {
+		holiday := helloworldv1.ScheduleEntry{
+			Start:   &metav1.Time{Time: monday.Add(12 * time.Hour)},
+			End:     &metav1.Time{Time: monday.Add(36 * time.Hour)},
+			Message: "closed for the holiday",
+		}
+		hw := newHelloWorld(holiday, officeHours)
+
+		m, a, n, err := scheduledMessage(hw, monday.Add(10*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m).To(Equal("open"))
+		Expect(*a).To(Equal(int32(1)))
+		Expect(*n).To(BeTemporally("==", monday.Add(12*time.Hour)))
+
+		m, a, n, err = scheduledMessage(hw, monday.Add(12*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m).To(Equal("closed for the holiday"))
+		Expect(*a).To(Equal(int32(0)))
+		Expect(*n).To(BeTemporally("==", monday.Add(36*time.Hour)))
+	})
+
+	It("never transitions after the last one-off window", func() {
+		hw := newHelloWorld(helloworldv1.ScheduleEntry{
+			Start:   &metav1.Time{Time: monday},
+			End:     &metav1.Time{Time: monday.Add(time.Hour)},
+			Message: "launch",
+		})
+		m, a, n, err := scheduledMessage(hw, monday.Add(2*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m).To(Equal("default"))
+		Expect(a).To(BeNil())
+		Expect(n).To(BeNil())
+	})
+
+	It("rejects invalid cron expressions", func() {
+		_, _, _, err := scheduledMessage(newHelloWorld(helloworldv1.ScheduleEntry{
+			Cron:     "every morning",
+			Duration: &metav1.Duration{Duration: time.Hour},
+			Message:  "hello",
+		}), monday)
+		Expect(err).To(MatchError(ContainSubstring("invalid cron expression")))
+	})
+
+	It("rejects cron expressions that never fire", func() {
+		_, _, _, err := scheduledMessage(newHelloWorld(helloworldv1.ScheduleEntry{
+			Cron:     "0 0 30 2 *",
+			Duration: &metav1.Duration{Duration: time.Hour},
+			Message:  "hello",
+		}), monday)
+		Expect(err).To(MatchError(ContainSubstring(`cron expression "0 0 30 2 *" never fires`)))
+	})
+})
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"math"
+
+	"k8s.io/utils/ptr"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+// helloWorldRolloutPlan is what each nginx track serves during a blue/green
+// or canary rollout, and how traffic is split between the tracks.
+type helloWorldRolloutPlan struct {
+	phase helloworldv1.StrategyPhase
+
+	stable         helloworldv1.HelloWorldRevision
+	stableReplicas int32
+
+	// canary is nil when no candidate revision is served
+	canary         *helloworldv1.HelloWorldRevision
+	canaryReplicas int32
+	canaryWeight   int32
+
+	// weightedSelection splits traffic by replica counts behind the stable
+	// Service instead of by Route weights
+	weightedSelection bool
+}
+
+// planHelloWorldRollout decides how the desired revision is rolled out. With
+// the default RollingUpdate strategy it simply replaces the stable revision.
+// With BlueGreen and Canary, content that differs from the stable revision
+// becomes a candidate served by the canary track until spec.strategy.promote
+// names it or spec.strategy.abort is set. Image changes are not staged and
+// apply to both tracks.
+func planHelloWorldRollout(
+	hw *helloworldv1.HelloWorld, desired helloworldv1.HelloWorldRevision, routes bool,
+) helloWorldRolloutPlan {
+	replicas := ptr.Deref(hw.Spec.Replicas, 1)
+	plan := helloWorldRolloutPlan{
+		phase:             helloworldv1.StrategyPhaseStable,
+		stable:            desired,
+		stableReplicas:    replicas,
+		weightedSelection: !routes,
+	}
+
+	strategy := hw.Spec.Strategy
+	if strategy == nil || strategy.Type == "" || strategy.Type == helloworldv1.RollingUpdateStrategyType {
+		return plan
+	}
+
+	status := hw.Status.Strategy
+	if status == nil || status.StableRevision == "" {
+		// Nothing has been served yet, so there is nothing to stage against
+		return plan
+	}
+	plan.stable.Content = status.StableRevision
+
+	switch {
+	case desired.Content == status.StableRevision:
+		// A promoted candidate keeps all traffic until the stable track has
+		// caught up, see settle
+		if status.Phase == helloworldv1.StrategyPhasePromoting {
+			plan.phase = helloworldv1.StrategyPhasePromoting
+			plan.canary = &desired
+			plan.canaryWeight = 100
+		}
+
+	case strategy.Abort:
+		plan.phase = helloworldv1.StrategyPhaseAborted
+
+	case strategy.Promote == desired.Content:
+		plan.phase = helloworldv1.StrategyPhasePromoting
+		plan.stable = desired
+		plan.canary = &desired
+		plan.canaryWeight = 100
+
+	case strategy.Type == helloworldv1.CanaryStrategyType:
+		plan.phase = helloworldv1.StrategyPhaseCanary
+		plan.canary = &desired
+		if strategy.Canary != nil {
+			plan.canaryWeight = strategy.Canary.Weight
+		}
+
+	default:
+		plan.phase = helloworldv1.StrategyPhasePreview
+		plan.canary = &desired
+	}
+
+	plan.canaryReplicas = replicas
+	if plan.weightedSelection && plan.canaryWeight > 0 && plan.canaryWeight < 100 {
+		// Approximate the weight with the share of replicas behind the Service
+		plan.canaryReplicas = int32(math.Ceil(float64(replicas) * float64(plan.canaryWeight) / 100))
+		plan.stableReplicas = max(1, replicas-plan.canaryReplicas)
+	}
+
+	return plan
+}
+
+// settle ends a promotion once the stable track serves the promoted revision.
+func (p *helloWorldRolloutPlan) settle() {
+	if p.phase != helloworldv1.StrategyPhasePromoting {
+		return
+	}
+	p.phase = helloworldv1.StrategyPhaseStable
+	p.canary = nil
+	p.canaryWeight = 0
+}
+
+// stableServiceSelector returns the pod selector of the stable Service, which
+// is the entry point for all traffic when Routes are not available. In that
+// case it selects the pods of whichever tracks should receive traffic.
+// Unlike the selector of the stable Deployment, which is immutable, it
+// always selects the name of the HelloWorld, so that the Service does not
+// serve the pods of the other HelloWorlds of the namespace.
+func (p *helloWorldRolloutPlan) stableServiceSelector(hw *helloworldv1.HelloWorld) map[string]string {
+	switch {
+	case !p.weightedSelection || p.canary == nil || p.canaryWeight == 0:
+		selector := helloWorldPodSelector(hw, helloWorldStableTrack)
+		selector[helloWorldNameLabelKey] = helloWorldNameLabel(hw)
+		return selector
+	case p.canaryWeight == 100:
+		return helloWorldPodSelector(hw, helloWorldCanaryTrack)
+	default:
+		return map[string]string{helloWorldNameLabelKey: helloWorldNameLabel(hw)}
+	}
+}
+
+// stableServiceRevisions returns the revisions served by the pods behind the
+// stable Service, following stableServiceSelector.
+func (p *helloWorldRolloutPlan) stableServiceRevisions() []helloworldv1.HelloWorldRevision {
+	switch {
+	case !p.weightedSelection || p.canary == nil || p.canaryWeight == 0:
+		return []helloworldv1.HelloWorldRevision{p.stable}
+	case p.canaryWeight == 100:
+		return []helloworldv1.HelloWorldRevision{*p.canary}
+	default:
+		return []helloworldv1.HelloWorldRevision{p.stable, *p.canary}
+	}
+}
+
+// status returns the rollout progress to report, nil for RollingUpdate.
+func (p *helloWorldRolloutPlan) status(hw *helloworldv1.HelloWorld) *helloworldv1.StrategyStatus {
+	if hw.Spec.Strategy == nil || hw.Spec.Strategy.Type == "" || hw.Spec.Strategy.Type == helloworldv1.RollingUpdateStrategyType {
+		return nil
+	}
+
+	status := &helloworldv1.StrategyStatus{
+		Phase:          p.phase,
+		StableRevision: p.stable.Content,
+		CanaryWeight:   p.canaryWeight,
+	}
+	if p.canary != nil {
+		status.CandidateRevision = p.canary.Content
+	}
+
+	return status
+}
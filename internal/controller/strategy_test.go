@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("HelloWorld rollout strategies", func() {
+	const image = "quay.io/org/nginx@sha256:1111"
+
+	newHelloWorld := func(strategy *helloworldv1.StrategySpec, status *helloworldv1.StrategyStatus) *helloworldv1.HelloWorld {
+		return &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{Name: "hw", Namespace: "default"},
+			Spec: helloworldv1.HelloWorldSpec{
+				Replicas: ptr.To[int32](4),
+				Strategy: strategy,
+			},
+			Status: helloworldv1.HelloWorldStatus{
+				Strategy: status,
+			},
+		}
+	}
+	stable := &helloworldv1.StrategyStatus{
+		Phase:          helloworldv1.StrategyPhaseStable,
+		StableRevision: "hw-html-old",
+	}
+	desired := helloworldv1.HelloWorldRevision{Content: "hw-html-new", Image: image}
+
+	It("replaces the stable revision with RollingUpdate", func() {
+		plan := planHelloWorldRollout(newHelloWorld(nil, stable), desired, true)
+		Expect(plan.stable).To(Equal(desired))
+		Expect(plan.canary).To(BeNil())
+		Expect(plan.status(newHelloWorld(nil, stable))).To(BeNil())
+	})
+
+	It("serves the first revision as stable", func() {
+		hw := newHelloWorld(&helloworldv1.StrategySpec{Type: helloworldv1.BlueGreenStrategyType}, nil)
+		plan := planHelloWorldRollout(hw, desired, true)
+		Expect(plan.phase).To(Equal(helloworldv1.StrategyPhaseStable))
+		Expect(plan.stable).To(Equal(desired))
+		Expect(plan.status(hw).StableRevision).To(Equal("hw-html-new"))
+	})
+
+	It("previews a blue/green candidate without traffic", func() {
+		hw := newHelloWorld(&helloworldv1.StrategySpec{Type: helloworldv1.BlueGreenStrategyType}, stable)
+		plan := planHelloWorldRollout(hw, desired, true)
+		Expect(plan.phase).To(Equal(helloworldv1.StrategyPhasePreview))
+		Expect(plan.stable.Content).To(Equal("hw-html-old"))
+		Expect(plan.canary).To(HaveValue(Equal(desired)))
+		Expect(plan.canaryWeight).To(BeZero())
+		Expect(plan.canaryReplicas).To(Equal(int32(4)))
+		Expect(plan.stableServiceSelector(hw)).To(Equal(map[string]string{
+			helloWorldAppLabelKey:  helloWorldAppLabelVal,
+			helloWorldNameLabelKey: "hw",
+		}))
+	})
+
+	It("sends the canary weight to the candidate", func() {
+		hw := newHelloWorld(&helloworldv1.StrategySpec{
+			Type:   helloworldv1.CanaryStrategyType,
+			Canary: &helloworldv1.CanaryStrategy{Weight: 25},
+		}, stable)
+		plan := planHelloWorldRollout(hw, desired, true)
+		Expect(plan.phase).To(Equal(helloworldv1.StrategyPhaseCanary))
+		Expect(plan.canaryWeight).To(Equal(int32(25)))
+		Expect(plan.status(hw)).To(Equal(&helloworldv1.StrategyStatus{
+			Phase:             helloworldv1.StrategyPhaseCanary,
+			StableRevision:    "hw-html-old",
+			CandidateRevision: "hw-html-new",
+			CanaryWeight:      25,
+		}))
+	})
+
+	It("splits replicas behind the Service without Routes", func() {
+		hw := newHelloWorld(&helloworldv1.StrategySpec{
+			Type:   helloworldv1.CanaryStrategyType,
+			Canary: &helloworldv1.CanaryStrategy{Weight: 25},
+		}, stable)
+		plan := planHelloWorldRollout(hw, desired, false)
+		Expect(plan.canaryReplicas).To(Equal(int32(1)))
+		Expect(plan.stableReplicas).To(Equal(int32(3)))
+		Expect(plan.stableServiceSelector(hw)).To(Equal(map[string]string{helloWorldNameLabelKey: "hw"}))
+	})
+
+	It("promotes the candidate named in the spec", func() {
+		hw := newHelloWorld(&helloworldv1.StrategySpec{
+			Type:    helloworldv1.BlueGreenStrategyType,
+			Promote: "hw-html-new",
+		}, stable)
+		plan := planHelloWorldRollout(hw, desired, false)
+		Expect(plan.phase).To(Equal(helloworldv1.StrategyPhasePromoting))
+		Expect(plan.stable).To(Equal(desired))
+		Expect(plan.canaryWeight).To(Equal(int32(100)))
+		Expect(plan.stableServiceSelector(hw)).To(Equal(helloWorldPodSelector(hw, helloWorldCanaryTrack)))
+
+		By("keeping traffic on the candidate until the stable track has rolled out")
+		hw.Status.Strategy = plan.status(hw)
+		plan = planHelloWorldRollout(hw, desired, false)
+		Expect(plan.phase).To(Equal(helloworldv1.StrategyPhasePromoting))
+
+		plan.settle()
+		Expect(plan.phase).To(Equal(helloworldv1.StrategyPhaseStable))
+		Expect(plan.canary).To(BeNil())
+	})
+
+	It("returns all traffic to the stable revision when aborted", func() {
+		hw := newHelloWorld(&helloworldv1.StrategySpec{
+			Type:   helloworldv1.CanaryStrategyType,
+			Canary: &helloworldv1.CanaryStrategy{Weight: 50},
+			Abort:  true,
+		}, stable)
+		plan := planHelloWorldRollout(hw, desired, true)
+		Expect(plan.phase).To(Equal(helloworldv1.StrategyPhaseAborted))
+		Expect(plan.stable.Content).To(Equal("hw-html-old"))
+		Expect(plan.canary).To(BeNil())
+		Expect(plan.canaryWeight).To(BeZero())
+	})
+})
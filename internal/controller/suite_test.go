@@ -22,18 +22,24 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	routev1 "github.com/openshift/api/route/v1"
 
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/image"
+	"github.com/opendatahub-io/sample-component/internal/image/registrytest"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -59,7 +65,10 @@ var _ = BeforeSuite(func() {
 
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "config", "crd", "bases"),
+			filepath.Join("..", "..", "test", "crds"),
+		},
 		ErrorIfCRDPathMissing: true,
 
 		// The BinaryAssetsDirectory is only required if you want to run the tests directly
@@ -79,6 +88,8 @@ var _ = BeforeSuite(func() {
 
 	err = helloworldv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = routev1.Install(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:scheme
 
@@ -94,3 +105,30 @@ var _ = AfterSuite(func() {
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
 })
+
+// startRegistry starts a local registry serving the nginx image the
+// HelloWorlds of the tests run, and closes it once the spec ends.
+func startRegistry() *registrytest.Registry {
+	registry := registrytest.New()
+	DeferCleanup(registry.Close)
+	registry.Push("nginxinc/nginx-unprivileged", "latest", "sha256:1111")
+	return registry
+}
+
+// nginxImage returns the reference of the nginx image served by registry.
+func nginxImage(registry *registrytest.Registry) string {
+	return registry.Host() + "/nginxinc/nginx-unprivileged:latest"
+}
+
+// newReconciler returns a HelloWorldReconciler for the test environment,
+// resolving images with registry and telling the time with a fake clock.
+// Specs set the other fields they need on the returned reconciler.
+func newReconciler(registry *registrytest.Registry) *HelloWorldReconciler {
+	return &HelloWorldReconciler{
+		Client:   k8sClient,
+		Scheme:   k8sClient.Scheme(),
+		Recorder: record.NewFakeRecorder(10),
+		Resolver: &image.RegistryResolver{Client: registry.Client()},
+		Clock:    clocktesting.NewFakePassiveClock(time.Now()),
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health provides the readiness and liveness checks the manager
+// serves on its health probe endpoints.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// CacheSyncer is the part of a cache the cache sync check needs.
+type CacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// CacheSync returns a check passing once the informers of the cache have
+// synced, waiting at most timeout for them.
+func CacheSync(cache CacheSyncer, timeout time.Duration) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		if !cache.WaitForCacheSync(ctx) {
+			return errors.New("informer caches have not synced")
+		}
+		return nil
+	}
+}
+
+// APIGroups returns a check passing while the API server serves each of the
+// group versions.
+func APIGroups(dc discovery.DiscoveryInterface, groupVersions ...schema.GroupVersion) healthz.Checker {
+	return func(*http.Request) error {
+		for _, gv := range groupVersions {
+			_, err := dc.ServerResourcesForGroupVersion(gv.String())
+			if err != nil {
+				return fmt.Errorf("API %s is not served: %w", gv, err)
+			}
+		}
+		return nil
+	}
+}
+
+// LeaderElection returns a check passing once the manager is elected, which
+// it learns from elected being closed, or while another replica holds the
+// lease. Standby replicas are ready so that rolling updates can proceed, but
+// one that fails to take over an expired lease is not.
+func LeaderElection(elected <-chan struct{}, reader client.Reader, lease client.ObjectKey, clock clock.PassiveClock) healthz.Checker {
+	return func(req *http.Request) error {
+		select {
+		case <-elected:
+			return nil
+		default:
+		}
+
+		l := &coordinationv1.Lease{}
+		err := reader.Get(req.Context(), lease, l)
+		if err != nil {
+			return fmt.Errorf("failed to get leader election lease %s: %w", lease, err)
+		}
+		holder, renewed, duration := l.Spec.HolderIdentity, l.Spec.RenewTime, l.Spec.LeaseDurationSeconds
+		if holder == nil || *holder == "" || renewed == nil || duration == nil {
+			return fmt.Errorf("leader election lease %s is not held", lease)
+		}
+		expiry := renewed.Add(time.Duration(*duration) * time.Second)
+		if clock.Now().After(expiry) {
+			return fmt.Errorf("leader election lease %s held by %s expired at %s",
+				lease, *holder, expiry.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// Workqueue returns a check failing once a worker of the named controller
+// has been processing the same item for longer than threshold, according to
+// the workqueue_longest_running_processor_seconds metric in gatherer. Such a
+// workqueue is stalled, since reconciles return within their timeout.
+func Workqueue(gatherer prometheus.Gatherer, controller string, threshold time.Duration) healthz.Checker {
+	return func(*http.Request) error {
+		families, err := gatherer.Gather()
+		if err != nil {
+			return fmt.Errorf("failed to gather workqueue metrics: %w", err)
+		}
+		for _, family := range families {
+			if family.GetName() != "workqueue_longest_running_processor_seconds" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				if !hasLabel(metric, "name", controller) {
+					continue
+				}
+				running := time.Duration(metric.GetGauge().GetValue() * float64(time.Second))
+				if running > threshold {
+					return fmt.Errorf("a worker of controller %s has been processing an item for %s",
+						controller, running.Round(time.Second))
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func hasLabel(metric *dto.Metric, name, value string) bool {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue() == value
+		}
+	}
+	return false
+}
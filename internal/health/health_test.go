@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// syncer is a cache whose informers have synced once synced is closed.
+type syncer struct {
+	synced chan struct{}
+}
+
+func (s *syncer) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-s.synced:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+var _ = Describe("CacheSync", func() {
+	It("passes once the informers have synced", func() {
+		cache := &syncer{synced: make(chan struct{})}
+		check := CacheSync(cache, 10*time.Millisecond)
+
+		Expect(check(httptest.NewRequest("GET", "/readyz", nil))).To(MatchError(ContainSubstring("not synced")))
+
+		close(cache.synced)
+		Expect(check(httptest.NewRequest("GET", "/readyz", nil))).To(Succeed())
+	})
+})
+
+var _ = Describe("APIGroups", func() {
+	It("passes while every group version is served", func() {
+		dc := &fake.FakeDiscovery{Fake: &clienttesting.Fake{
+			Resources: []*metav1.APIResourceList{{GroupVersion: "apps/v1"}},
+		}}
+		apps := schema.GroupVersion{Group: "apps", Version: "v1"}
+		routes := schema.GroupVersion{Group: "route.openshift.io", Version: "v1"}
+
+		Expect(APIGroups(dc, apps)(httptest.NewRequest("GET", "/readyz", nil))).To(Succeed())
+		Expect(APIGroups(dc, apps, routes)(httptest.NewRequest("GET", "/readyz", nil))).
+			To(MatchError(ContainSubstring("API route.openshift.io/v1 is not served")))
+	})
+})
+
+var _ = Describe("LeaderElection", func() {
+	ctx := context.Background()
+	key := client.ObjectKey{Name: "0e3e0fcd.opendatahub.io", Namespace: "system"}
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+
+	var cli client.Client
+	var elected chan struct{}
+	var check func() error
+
+	BeforeEach(func() {
+		cli = fakeclient.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+		elected = make(chan struct{})
+		checker := LeaderElection(elected, cli, key, clocktesting.NewFakePassiveClock(now))
+		check = func() error {
+			return checker(httptest.NewRequest("GET", "/readyz", nil))
+		}
+	})
+
+	createLease := func(holder string, renewed time.Time) {
+		Expect(cli.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(holder),
+				LeaseDurationSeconds: ptr.To(int32(15)),
+				RenewTime:            ptr.To(metav1.NewMicroTime(renewed)),
+			},
+		})).To(Succeed())
+	}
+
+	It("passes once elected", func() {
+		close(elected)
+		Expect(check()).To(Succeed())
+	})
+
+	It("passes while another replica holds the lease", func() {
+		createLease("other", now.Add(-10*time.Second))
+		Expect(check()).To(Succeed())
+	})
+
+	It("fails while nobody holds the lease", func() {
+		Expect(check()).To(MatchError(ContainSubstring("failed to get leader election lease")))
+
+		createLease("", now)
+		Expect(check()).To(MatchError(ContainSubstring("is not held")))
+	})
+
+	It("fails once the lease of another replica expired", func() {
+		createLease("other", now.Add(-20*time.Second))
+		Expect(check()).To(MatchError(ContainSubstring("held by other expired")))
+	})
+})
+
+var _ = Describe("Workqueue", func() {
+	var registry *prometheus.Registry
+	var longestRunning *prometheus.GaugeVec
+
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		longestRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "workqueue_longest_running_processor_seconds",
+		}, []string{"name"})
+		registry.MustRegister(longestRunning)
+	})
+
+	It("passes before the controller has started", func() {
+		Expect(Workqueue(registry, "helloworld", time.Minute)(httptest.NewRequest("GET", "/healthz", nil))).To(Succeed())
+	})
+
+	It("fails once a worker of the controller is stuck on an item", func() {
+		check := Workqueue(registry, "helloworld", time.Minute)
+		longestRunning.WithLabelValues("helloworld").Set(30)
+		longestRunning.WithLabelValues("other").Set(3600)
+		Expect(check(httptest.NewRequest("GET", "/healthz", nil))).To(Succeed())
+
+		longestRunning.WithLabelValues("helloworld").Set(90)
+		Expect(check(httptest.NewRequest("GET", "/healthz", nil))).
+			To(MatchError("a worker of controller helloworld has been processing an item for 1m30s"))
+	})
+})
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package image parses container image references, resolves their tags to
+// digests and checks them against a registry allowlist.
+package image
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultRegistry is the registry used for references without an explicit registry host
+	DefaultRegistry = "docker.io"
+	defaultTag      = "latest"
+)
+
+// Reference is a parsed container image reference.
+type Reference struct {
+	// Registry is the registry host, e.g. docker.io or quay.io:443
+	Registry string
+	// Repository is the repository path within the registry, e.g. library/nginx
+	Repository string
+	// Tag is the image tag, empty when the reference only carries a digest
+	Tag string
+	// Digest is the content digest, e.g. sha256:abcd...
+	Digest string
+}
+
+// Parse parses an image reference such as "nginx", "quay.io/org/app:v1" or
+// "registry:5000/app@sha256:...". References without a registry default to
+// docker.io and references without a tag or digest default to the latest tag.
+func Parse(ref string) (Reference, error) {
+	if ref == "" || strings.TrimSpace(ref) != ref {
+		return Reference{}, fmt.Errorf("invalid image reference %q", ref)
+	}
+
+	r := Reference{}
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		r.Digest = name[i+1:]
+		name = name[:i]
+		if !strings.Contains(r.Digest, ":") {
+			return Reference{}, fmt.Errorf("invalid digest in image reference %q", ref)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		r.Tag = name[i+1:]
+		name = name[:i]
+		if r.Tag == "" {
+			return Reference{}, fmt.Errorf("invalid tag in image reference %q", ref)
+		}
+	}
+
+	// The first path component is a registry host only if it looks like one,
+	// mirroring the rules used by the docker CLI.
+	first, rest, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		r.Registry = first
+		r.Repository = rest
+	} else {
+		r.Registry = DefaultRegistry
+		r.Repository = name
+		if !found {
+			r.Repository = "library/" + name
+		}
+	}
+	if r.Repository == "" || strings.HasSuffix(r.Repository, "/") {
+		return Reference{}, fmt.Errorf("invalid image reference %q", ref)
+	}
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = defaultTag
+	}
+
+	return r, nil
+}
+
+// Name returns the reference without its tag or digest.
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// String returns the fully qualified reference.
+func (r Reference) String() string {
+	s := r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// Pinned returns the reference pinned to the given digest, dropping the tag.
+func (r Reference) Pinned(digest string) string {
+	return r.Name() + "@" + digest
+}
+
+// Allowed reports whether the reference comes from one of the allowed
+// registries. Entries match either a registry host (quay.io) or a repository
+// prefix within a registry (quay.io/my-org). An empty allowlist allows every
+// registry.
+func (r Reference) Allowed(allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		a = strings.TrimSuffix(a, "/")
+		if a == r.Registry || strings.HasPrefix(r.Name(), a+"/") {
+			return true
+		}
+	}
+	return false
+}
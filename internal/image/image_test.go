@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendatahub-io/sample-component/internal/image/registrytest"
+)
+
+var _ = Describe("Image references", func() {
+	DescribeTable("parsing",
+		func(ref string, expected Reference) {
+			r, err := Parse(ref)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r).To(Equal(expected))
+		},
+		Entry("short docker hub name", "nginx",
+			Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}),
+		Entry("docker hub organisation", "nginxinc/nginx-unprivileged:latest",
+			Reference{Registry: "docker.io", Repository: "nginxinc/nginx-unprivileged", Tag: "latest"}),
+		Entry("registry with port", "localhost:5000/app:v1",
+			Reference{Registry: "localhost:5000", Repository: "app", Tag: "v1"}),
+		Entry("nested repository", "quay.io/org/team/app:1.2",
+			Reference{Registry: "quay.io", Repository: "org/team/app", Tag: "1.2"}),
+		Entry("digest only", "quay.io/org/app@sha256:abc",
+			Reference{Registry: "quay.io", Repository: "org/app", Digest: "sha256:abc"}),
+		Entry("tag and digest", "quay.io/org/app:v1@sha256:abc",
+			Reference{Registry: "quay.io", Repository: "org/app", Tag: "v1", Digest: "sha256:abc"}),
+	)
+
+	DescribeTable("rejecting invalid references",
+		func(ref string) {
+			_, err := Parse(ref)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("empty tag", "nginx:"),
+		Entry("malformed digest", "nginx@abc"),
+		Entry("whitespace", " nginx"),
+		Entry("missing repository", "quay.io/"),
+	)
+
+	DescribeTable("registry allowlist",
+		func(ref string, allowed []string, expected bool) {
+			r, err := Parse(ref)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Allowed(allowed)).To(Equal(expected))
+		},
+		Entry("empty allowlist", "nginx", nil, true),
+		Entry("registry host", "quay.io/org/app", []string{"quay.io"}, true),
+		Entry("implicit docker hub", "nginx", []string{"docker.io"}, true),
+		Entry("repository prefix", "quay.io/org/app", []string{"quay.io/org/"}, true),
+		Entry("other organisation", "quay.io/other/app", []string{"quay.io/org"}, false),
+		Entry("prefix of another host", "quay.io.evil.com/org/app", []string{"quay.io"}, false),
+	)
+
+	It("pins a reference to a digest", func() {
+		r, err := Parse("nginx:1.27")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Pinned("sha256:abc")).To(Equal("docker.io/library/nginx@sha256:abc"))
+	})
+})
+
+var _ = Describe("RegistryResolver", func() {
+	var registry *registrytest.Registry
+	var resolver *RegistryResolver
+
+	BeforeEach(func() {
+		registry = registrytest.New()
+		resolver = &RegistryResolver{Client: registry.Client()}
+	})
+
+	AfterEach(func() {
+		registry.Close()
+	})
+
+	It("resolves a tag to the digest the registry returns", func() {
+		registry.Push("org/app", "v1", "sha256:1111")
+
+		digest, err := resolver.Resolve(context.Background(), Reference{
+			Registry: registry.Host(), Repository: "org/app", Tag: "v1",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal("sha256:1111"))
+	})
+
+	It("requests an anonymous token when challenged", func() {
+		registry.RequireToken = true
+		registry.Push("org/app", "v1", "sha256:2222")
+
+		digest, err := resolver.Resolve(context.Background(), Reference{
+			Registry: registry.Host(), Repository: "org/app", Tag: "v1",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal("sha256:2222"))
+	})
+
+	It("requests a token for every action of a challenged scope", func() {
+		registry.RequireToken = true
+		registry.Scope = "repository:org/app:pull,push"
+		registry.Push("org/app", "v1", "sha256:4444")
+
+		digest, err := resolver.Resolve(context.Background(), Reference{
+			Registry: registry.Host(), Repository: "org/app", Tag: "v1",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal("sha256:4444"))
+	})
+
+	It("fails for unknown tags", func() {
+		_, err := resolver.Resolve(context.Background(), Reference{
+			Registry: registry.Host(), Repository: "org/app", Tag: "missing",
+		})
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+
+	It("does not contact the registry for references that carry a digest", func() {
+		digest, err := resolver.Resolve(context.Background(), Reference{
+			Registry: registry.Host(), Repository: "org/app", Digest: "sha256:3333",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal("sha256:3333"))
+		Expect(registry.Requests()).To(BeZero())
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registrytest provides an in-process container registry that serves
+// manifest digests, for testing code that resolves image tags.
+package registrytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const testToken = "registrytest-token"
+
+// Registry is a minimal OCI distribution API that only answers manifest HEAD
+// and GET requests for the tags pushed to it. When RequireToken is set it
+// challenges unauthenticated requests the way Docker Hub does.
+type Registry struct {
+	// RequireToken makes the registry demand an anonymous bearer token
+	RequireToken bool
+	// Scope is the scope the registry challenges for, and the only one it
+	// grants tokens for once set. It is repository:<repository>:pull if empty.
+	Scope string
+
+	server *httptest.Server
+
+	mu        sync.Mutex
+	manifests map[string]string
+	requests  int
+}
+
+// New starts a registry serving TLS on a local port. Callers must Close it.
+func New() *Registry {
+	r := &Registry{manifests: map[string]string{}}
+	r.server = httptest.NewTLSServer(http.HandlerFunc(r.serveHTTP))
+	return r
+}
+
+// Host returns the host:port the registry listens on, usable as the registry
+// part of an image reference.
+func (r *Registry) Host() string {
+	return strings.TrimPrefix(r.server.URL, "https://")
+}
+
+// Client returns an HTTP client that trusts the registry's certificate.
+func (r *Registry) Client() *http.Client {
+	return r.server.Client()
+}
+
+// Close shuts the registry down.
+func (r *Registry) Close() {
+	r.server.Close()
+}
+
+// Push makes repository:tag resolve to digest, replacing any previous digest.
+func (r *Registry) Push(repository, tag, digest string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifests[repository+":"+tag] = digest
+}
+
+// Requests returns the number of manifest requests served so far.
+func (r *Registry) Requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests
+}
+
+func (r *Registry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		if r.Scope != "" && req.URL.Query().Get("scope") != r.Scope {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": testToken})
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	i := strings.LastIndex(path, "/manifests/")
+	if i < 0 || path == req.URL.Path {
+		http.NotFound(w, req)
+		return
+	}
+	repository, tag := path[:i], path[i+len("/manifests/"):]
+
+	if r.RequireToken && req.Header.Get("Authorization") != "Bearer "+testToken {
+		scope := r.Scope
+		if scope == "" {
+			scope = "repository:" + repository + ":pull"
+		}
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+			`Bearer realm="%s/token",service="registrytest",scope="%s"`, r.server.URL, scope))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	r.mu.Lock()
+	r.requests++
+	digest, ok := r.manifests[repository+":"+tag]
+	r.mu.Unlock()
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Resolver resolves an image reference to the digest of the manifest it
+// currently points to.
+type Resolver interface {
+	Resolve(ctx context.Context, ref Reference) (string, error)
+}
+
+// manifestMediaTypes are the manifest types accepted when resolving a tag. The
+// index types come first so multi-arch images resolve to the index digest.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// RegistryResolver resolves tags against the OCI distribution API of the
+// registry hosting the image. Anonymous bearer tokens are requested when the
+// registry asks for them, which covers public images on Docker Hub and quay.io.
+type RegistryResolver struct {
+	// Client is the HTTP client used to talk to registries, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// Resolve implements Resolver.
+func (r *RegistryResolver) Resolve(ctx context.Context, ref Reference) (string, error) {
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	host := ref.Registry
+	if host == DefaultRegistry {
+		host = "registry-1.docker.io"
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, ref.Repository, ref.Tag)
+
+	resp, err := r.head(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := r.token(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", fmt.Errorf("failed to authenticate to %s: %w", ref.Registry, err)
+		}
+		if resp, err = r.head(ctx, manifestURL, token); err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to resolve %s: registry returned %s", ref, resp.Status)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("failed to resolve %s: registry did not return a digest", ref)
+	}
+
+	return digest, nil
+}
+
+func (r *RegistryResolver) client() *http.Client {
+	if r.Client != nil {
+		return r.Client
+	}
+	return http.DefaultClient
+}
+
+func (r *RegistryResolver) head(ctx context.Context, manifestURL string, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	return resp, nil
+}
+
+// token fetches an anonymous token from the realm advertised in a
+// "WWW-Authenticate: Bearer realm=...,service=...,scope=..." challenge.
+func (r *RegistryResolver) token(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	values := challengeParams(params)
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm in challenge %q", challenge)
+	}
+	q := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if values[k] != "" {
+			q.Set(k, values[k])
+		}
+	}
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+
+	body := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// challengeParams parses the comma-separated auth-params of a challenge into
+// a map keyed by lower-case name. Values are tokens or quoted strings, which
+// may hold commas, as in scope="repository:org/app:pull,push", and escape
+// characters with a backslash.
+func challengeParams(params string) map[string]string {
+	values := map[string]string{}
+	for {
+		params = strings.TrimLeft(params, " \t,")
+		eq := strings.IndexByte(params, '=')
+		if eq < 0 {
+			return values
+		}
+		if comma := strings.IndexByte(params[:eq], ','); comma >= 0 {
+			// Skip a param without a value
+			params = params[comma:]
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(params[:eq]))
+		params = strings.TrimLeft(params[eq+1:], " \t")
+
+		var value strings.Builder
+		if strings.HasPrefix(params, `"`) {
+			i := 1
+			for ; i < len(params) && params[i] != '"'; i++ {
+				if params[i] == '\\' && i+1 < len(params) {
+					i++
+				}
+				value.WriteByte(params[i])
+			}
+			params = params[min(i+1, len(params)):]
+		} else {
+			end := strings.IndexAny(params, ", \t")
+			if end < 0 {
+				end = len(params)
+			}
+			value.WriteString(params[:end])
+			params = params[end:]
+		}
+		values[name] = value.String()
+	}
+}
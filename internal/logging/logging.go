@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging builds the controller's logger from the zap flags and a
+// preset, and lets a reconcile raise the verbosity of its own logs, so one
+// custom resource can be debugged without turning on debug logs for all.
+package logging
+
+import (
+	"flag"
+	"fmt"
+	"math"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// InfoLevel is the verbosity of info logs
+	InfoLevel = 0
+	// DebugLevel is the verbosity of debug logs, written with V(DebugLevel)
+	DebugLevel = 1
+)
+
+// Preset is a set of defaults for the zap flags, usable as a flag.Value.
+type Preset string
+
+const (
+	// DevelopmentPreset logs human-readable lines at debug level
+	DevelopmentPreset Preset = "development"
+	// ProductionPreset logs JSON lines at info level, sampling repeated ones
+	ProductionPreset Preset = "production"
+)
+
+// String implements flag.Value.
+func (p *Preset) String() string {
+	return string(*p)
+}
+
+// Set implements flag.Value.
+func (p *Preset) Set(value string) error {
+	switch Preset(value) {
+	case DevelopmentPreset, ProductionPreset:
+		*p = Preset(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown log preset %q, expected %s or %s", value, DevelopmentPreset, ProductionPreset)
+	}
+}
+
+// Apply sets the defaults of the preset on opts, unless --zap-devel was set
+// on fs. The other zap flags are applied on top of the preset as usual.
+func (p *Preset) Apply(opts *zap.Options, fs *flag.FlagSet) {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		set = set || f.Name == "zap-devel"
+	})
+	if !set {
+		opts.Development = *p == DevelopmentPreset
+	}
+}
+
+// ParseLevel parses the name of a log level, info or debug, into a verbosity.
+func ParseLevel(name string) (int, error) {
+	switch name {
+	case "info":
+		return InfoLevel, nil
+	case "debug":
+		return DebugLevel, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q, expected info or debug", name)
+	}
+}
+
+// New returns a zap logger configured by opts, whose verbosity can be raised
+// with WithVerbosity. zap is set to write debug logs too, and the logger
+// filters them down to the verbosity of the level set in opts.
+func New(opts *zap.Options) logr.Logger {
+	level := opts.Level
+	if level == nil {
+		level = zapcore.InfoLevel
+		if opts.Development {
+			level = zapcore.DebugLevel
+		}
+	}
+
+	// The verbosity of zap level -n is n, and -1 disables info logs
+	verbosity := -1
+	for verbosity < math.MaxInt8 && level.Enabled(zapcore.Level(-verbosity-1)) {
+		verbosity++
+	}
+	if verbosity < DebugLevel {
+		opts.Level = zapcore.DebugLevel
+	}
+
+	// The wrapped logger skips its own frame, and sink adds one
+	return logr.New(&sink{
+		LogSink:   zap.New(zap.UseFlagOptions(opts)).WithCallDepth(1).GetSink(),
+		verbosity: verbosity,
+	})
+}
+
+// WithVerbosity returns logger writing info logs up to verbosity, if it was
+// built by New and the verbosity is higher than its own. Other loggers are
+// returned unchanged.
+func WithVerbosity(logger logr.Logger, verbosity int) logr.Logger {
+	s, ok := logger.GetSink().(*sink)
+	if !ok || verbosity <= s.verbosity {
+		return logger
+	}
+	return logger.WithSink(&sink{LogSink: s.LogSink, verbosity: verbosity})
+}
+
+// sink filters info logs by its own verbosity rather than by the one of the
+// sink it wraps.
+type sink struct {
+	logr.LogSink
+	verbosity int
+}
+
+var _ logr.CallDepthLogSink = &sink{}
+
+// Init implements logr.LogSink. The wrapped sink was initialized by the
+// logger it was taken from.
+func (s *sink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink.
+func (s *sink) Enabled(level int) bool {
+	return level <= s.verbosity
+}
+
+// Info implements logr.LogSink.
+func (s *sink) Info(level int, msg string, keysAndValues ...any) {
+	s.LogSink.Info(level, msg, keysAndValues...)
+}
+
+// Error implements logr.LogSink.
+func (s *sink) Error(err error, msg string, keysAndValues ...any) {
+	s.LogSink.Error(err, msg, keysAndValues...)
+}
+
+// WithValues implements logr.LogSink.
+func (s *sink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &sink{LogSink: s.LogSink.WithValues(keysAndValues...), verbosity: s.verbosity}
+}
+
+// WithName implements logr.LogSink.
+func (s *sink) WithName(name string) logr.LogSink {
+	return &sink{LogSink: s.LogSink.WithName(name), verbosity: s.verbosity}
+}
+
+// WithCallDepth implements logr.CallDepthLogSink.
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	withDepth, ok := s.LogSink.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	return &sink{LogSink: withDepth.WithCallDepth(depth), verbosity: s.verbosity}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"strings"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// lines decodes the JSON lines written to out.
+func lines(out *bytes.Buffer) []map[string]any {
+	var decoded []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := map[string]any{}
+		Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+		decoded = append(decoded, entry)
+	}
+	return decoded
+}
+
+var _ = Describe("Logger", func() {
+	var out *bytes.Buffer
+	var opts *zap.Options
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		// Callers are recorded to check the sink reports the right frame
+		opts = &zap.Options{DestWriter: out, ZapOpts: []uberzap.Option{uberzap.AddCaller()}}
+	})
+
+	It("writes JSON info logs with the production preset", func() {
+		logger := New(opts).WithValues("name", "hello")
+		logger.Info("Reconciling HelloWorld", "generation", 2)
+		logger.V(DebugLevel).Info("Serving revision")
+
+		entries := lines(out)
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0]).To(HaveKeyWithValue("msg", "Reconciling HelloWorld"))
+		Expect(entries[0]).To(HaveKeyWithValue("name", "hello"))
+		Expect(entries[0]).To(HaveKeyWithValue("generation", BeNumerically("==", 2)))
+		Expect(entries[0]).To(HaveKeyWithValue("caller", ContainSubstring("logging_test.go")))
+	})
+
+	It("writes debug logs of loggers with a raised verbosity", func() {
+		logger := New(opts)
+		debug := WithVerbosity(logger.WithValues("name", "noisy"), DebugLevel)
+		debug.V(DebugLevel).Info("Serving revision")
+		logger.V(DebugLevel).Info("Serving revision")
+
+		entries := lines(out)
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0]).To(HaveKeyWithValue("name", "noisy"))
+		Expect(entries[0]).To(HaveKeyWithValue("level", "debug"))
+		Expect(entries[0]).To(HaveKeyWithValue("caller", ContainSubstring("logging_test.go")))
+	})
+
+	It("never lowers the verbosity set by the flags", func() {
+		opts.Level = zapcore.DebugLevel
+		logger := WithVerbosity(New(opts), InfoLevel)
+		logger.V(DebugLevel).Info("Serving revision")
+
+		Expect(lines(out)).To(HaveLen(1))
+	})
+
+	It("writes only errors when info logs are disabled", func() {
+		opts.Level = zapcore.ErrorLevel
+		logger := New(opts)
+		logger.Info("Reconciling HelloWorld")
+		logger.Error(nil, "Failed to update HelloWorld status")
+
+		entries := lines(out)
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0]).To(HaveKeyWithValue("level", "error"))
+	})
+
+	It("leaves loggers it did not build alone", func() {
+		logger := logr.Discard()
+		Expect(WithVerbosity(logger, DebugLevel)).To(Equal(logger))
+	})
+})
+
+var _ = Describe("Preset", func() {
+	var fs *flag.FlagSet
+	var opts *zap.Options
+	var preset Preset
+
+	BeforeEach(func() {
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		opts = &zap.Options{}
+		opts.BindFlags(fs)
+		preset = DevelopmentPreset
+		fs.Var(&preset, "log-preset", "")
+	})
+
+	It("defaults to development logs", func() {
+		Expect(fs.Parse(nil)).To(Succeed())
+		preset.Apply(opts, fs)
+		Expect(opts.Development).To(BeTrue())
+	})
+
+	It("switches to production logs", func() {
+		Expect(fs.Parse([]string{"--log-preset=production"})).To(Succeed())
+		preset.Apply(opts, fs)
+		Expect(opts.Development).To(BeFalse())
+	})
+
+	It("lets --zap-devel override the preset", func() {
+		Expect(fs.Parse([]string{"--log-preset=production", "--zap-devel=true"})).To(Succeed())
+		preset.Apply(opts, fs)
+		Expect(opts.Development).To(BeTrue())
+	})
+
+	It("rejects unknown presets", func() {
+		fs.SetOutput(GinkgoWriter)
+		Expect(fs.Parse([]string{"--log-preset=verbose"})).To(MatchError(ContainSubstring("unknown log preset")))
+	})
+})
+
+var _ = DescribeTable("ParseLevel",
+	func(name string, verbosity int, fails bool) {
+		parsed, err := ParseLevel(name)
+		if fails {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed).To(Equal(verbosity))
+	},
+	Entry("info", "info", InfoLevel, false),
+	Entry("debug", "debug", DebugLevel, false),
+	Entry("unknown", "trace", 0, true),
+)
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package names generates the names of objects derived from the name of
+// another, such as the children of a custom resource. Generated names are
+// deterministic and stay within the length limits of the API server.
+package names
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Limits of the names and label values generated for children.
+const (
+	// MaxSubdomain is the maximum length of DNS subdomain names, such as
+	// those of ConfigMaps and Deployments
+	MaxSubdomain = validation.DNS1123SubdomainMaxLength
+	// MaxLabel is the maximum length of DNS label names, such as those of
+	// Services and Routes
+	MaxLabel = validation.DNS1123LabelMaxLength
+	// MaxLabelValue is the maximum length of a label value
+	MaxLabelValue = validation.LabelValueMaxLength
+)
+
+// hashLength is the number of hex digits of the hash that replaces the
+// truncated part of a name.
+const hashLength = 8
+
+// Child returns the name of a child of parent, the parent's name followed by
+// the suffixes and joined by dashes. If that is longer than max, the parent's
+// name is truncated and a hash of the full name inserted before the
+// suffixes, so that parents sharing a long prefix still get different names.
+// The same arguments always return the same name.
+func Child(max int, parent string, suffixes ...string) string {
+	name := strings.Join(append([]string{parent}, suffixes...), "-")
+	if len(name) <= max {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:hashLength]
+	suffix := hash
+	if len(suffixes) > 0 {
+		suffix += "-" + strings.Join(suffixes, "-")
+	}
+	if len(suffix)+1 >= max {
+		return strings.TrimRight(suffix[:min(len(suffix), max)], "-.")
+	}
+
+	prefix := strings.TrimRight(parent[:max-len(suffix)-1], "-.")
+	return prefix + "-" + suffix
+}
+
+// DNSLabel returns a name that is a valid DNS label, for kinds such as Services
+// that do not allow the dots of a subdomain name in their own name.
+func DNSLabel(parent string, suffixes ...string) string {
+	return Child(MaxLabel, strings.ReplaceAll(parent, ".", "-"), suffixes...)
+}
+
+// LabelValue returns a label value identifying the named object, its name if
+// it is short enough, a truncated and hashed one otherwise.
+func LabelValue(name string) string {
+	return Child(MaxLabelValue, name)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package names
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var _ = Describe("Generated names", func() {
+	long := strings.Repeat("a", 70)
+
+	It("joins short names as they are", func() {
+		Expect(Child(MaxSubdomain, "hello", "html", "0123456789")).To(Equal("hello-html-0123456789"))
+		Expect(DNSLabel("hello", "nginx")).To(Equal("hello-nginx"))
+		Expect(LabelValue("hello")).To(Equal("hello"))
+	})
+
+	It("truncates and hashes long names, keeping the suffixes", func() {
+		name := DNSLabel(long, "nginx", "canary")
+		Expect(name).To(HaveLen(MaxLabel))
+		Expect(name).To(HaveSuffix("-nginx-canary"))
+		Expect(name).To(HavePrefix("aaaa"))
+		Expect(validation.IsDNS1035Label(name)).To(BeEmpty())
+	})
+
+	It("is deterministic", func() {
+		Expect(DNSLabel(long, "nginx")).To(Equal(DNSLabel(long, "nginx")))
+	})
+
+	It("gives parents sharing a long prefix different names", func() {
+		Expect(DNSLabel(long+"-one", "nginx")).NotTo(Equal(DNSLabel(long+"-two", "nginx")))
+		Expect(LabelValue(long + "-one")).NotTo(Equal(LabelValue(long + "-two")))
+	})
+
+	It("does not end the truncated part with a separator", func() {
+		name := Child(MaxSubdomain, strings.Repeat("a", MaxSubdomain-17)+"-."+long, "nginx")
+		Expect(name).To(HavePrefix(strings.Repeat("a", MaxSubdomain-17) + "-"))
+		Expect(name).NotTo(ContainSubstring("--"))
+		Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+
+		name = DNSLabel(strings.Repeat("a", MaxLabel-17)+"-"+long, "nginx")
+		Expect(name).NotTo(ContainSubstring("--"))
+		Expect(validation.IsDNS1123Label(name)).To(BeEmpty())
+	})
+
+	It("replaces dots in DNS labels", func() {
+		Expect(DNSLabel("hello.world", "nginx")).To(Equal("hello-world-nginx"))
+	})
+
+	It("returns valid label values", func() {
+		value := LabelValue(strings.Repeat("a.", 100) + "b")
+		Expect(len(value)).To(BeNumerically("<=", MaxLabelValue))
+		Expect(validation.IsValidLabelValue(value)).To(BeEmpty())
+	})
+})
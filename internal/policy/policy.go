@@ -0,0 +1,288 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy combines the HelloWorldPolicies applying to a namespace and
+// checks HelloWorlds against them. The validating webhook rejects HelloWorlds
+// breaking a policy, and the controller serves the ones created before it
+// within its limits.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/image"
+)
+
+// +kubebuilder:rbac:groups=helloworld.opendatahub.io,resources=helloworldpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Effective returns the combination of the HelloWorldPolicies applying to
+// namespace, or nil if none does.
+func Effective(ctx context.Context, c client.Reader, namespace string) (*helloworldv1.AppliedPolicy, error) {
+	policies := &helloworldv1.HelloWorldPolicyList{}
+	err := c.List(ctx, policies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HelloWorldPolicies: %w", err)
+	}
+
+	var ns *corev1.Namespace
+	var applying []helloworldv1.HelloWorldPolicy
+	for _, p := range policies.Items {
+		if p.Spec.NamespaceSelector != nil && ns == nil {
+			ns = &corev1.Namespace{}
+			err = c.Get(ctx, client.ObjectKey{Name: namespace}, ns)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+			}
+		}
+		ok, err := applies(p, ns)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			applying = append(applying, p)
+		}
+	}
+
+	return Combine(applying), nil
+}
+
+// applies reports whether the policy applies to ns, which may only be nil
+// when the policy selects every namespace.
+func applies(p helloworldv1.HelloWorldPolicy, ns *corev1.Namespace) (bool, error) {
+	if p.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector of HelloWorldPolicy %s: %w", p.Name, err)
+	}
+
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// Combine returns the combination of policies: limits combine to the
+// strictest, and the defaults of policies earlier in name order take
+// precedence. It returns nil if there are no policies.
+func Combine(policies []helloworldv1.HelloWorldPolicy) *helloworldv1.AppliedPolicy {
+	if len(policies) == 0 {
+		return nil
+	}
+	policies = slices.Clone(policies)
+	slices.SortFunc(policies, func(a, b helloworldv1.HelloWorldPolicy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	applied := &helloworldv1.AppliedPolicy{}
+	for _, p := range policies {
+		spec := p.Spec
+		applied.Policies = append(applied.Policies, p.Name)
+		if spec.AllowedImages != nil {
+			applied.AllowedImages = intersectImages(applied.AllowedImages, spec.AllowedImages)
+		}
+		if applied.DefaultImage == "" {
+			applied.DefaultImage = spec.DefaultImage
+		}
+		applied.MaxReplicas = minimum(applied.MaxReplicas, spec.MaxReplicas)
+		for _, label := range spec.RequiredLabels {
+			if !slices.Contains(applied.RequiredLabels, label) {
+				applied.RequiredLabels = append(applied.RequiredLabels, label)
+			}
+		}
+		if spec.AllowedExposures != nil {
+			applied.AllowedExposures = intersect(applied.AllowedExposures, spec.AllowedExposures)
+		}
+		if applied.DefaultExposure == "" {
+			applied.DefaultExposure = spec.DefaultExposure
+		}
+		applied.MaxMessageSize = minimum(applied.MaxMessageSize, spec.MaxMessageSize)
+		applied.Quota = CombineQuotas(applied.Quota, spec.Quota)
+	}
+	slices.Sort(applied.RequiredLabels)
+
+	return applied
+}
+
+// intersectImages returns the images allowed by both allowlists, b if a is
+// nil. An image prefix is allowed by an allowlist that has it or one of its
+// parents, so the intersection keeps the narrower of every pair of related
+// prefixes.
+func intersectImages(a, b []string) []string {
+	if a == nil {
+		return slices.Clone(b)
+	}
+
+	result := []string{}
+	for _, x := range a {
+		for _, y := range b {
+			var narrower string
+			switch {
+			case underImage(x, y):
+				narrower = x
+			case underImage(y, x):
+				narrower = y
+			default:
+				continue
+			}
+			if !slices.Contains(result, narrower) {
+				result = append(result, narrower)
+			}
+		}
+	}
+
+	return result
+}
+
+// underImage reports whether the image prefix x is prefix, or lies within it.
+func underImage(x, prefix string) bool {
+	x, prefix = strings.TrimSuffix(x, "/"), strings.TrimSuffix(prefix, "/")
+	return x == prefix || strings.HasPrefix(x, prefix+"/")
+}
+
+// intersect returns the items in both a and b, b if a is nil.
+func intersect[T comparable](a, b []T) []T {
+	if a == nil {
+		return slices.Clone(b)
+	}
+
+	result := []T{}
+	for _, x := range a {
+		if slices.Contains(b, x) {
+			result = append(result, x)
+		}
+	}
+
+	return result
+}
+
+// minimum returns the smaller of two optional limits.
+func minimum(a, b *int32) *int32 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+// ImageAllowed reports whether the policy allows ref. Unlike an empty
+// registry allowlist, an empty policy allowlist allows no image.
+func ImageAllowed(p *helloworldv1.AppliedPolicy, ref image.Reference) bool {
+	if p == nil || p.AllowedImages == nil {
+		return true
+	}
+	return len(p.AllowedImages) > 0 && ref.Allowed(p.AllowedImages)
+}
+
+// ExposureAllowed reports whether the policy allows exposure.
+func ExposureAllowed(p *helloworldv1.AppliedPolicy, exposure helloworldv1.ExposureType) bool {
+	return p == nil || p.AllowedExposures == nil || slices.Contains(p.AllowedExposures, exposure)
+}
+
+// Validate checks hw against the policy. Its image and exposure default to
+// the ones of the policy, then to defaultImage and Route.
+func Validate(hw *helloworldv1.HelloWorld, p *helloworldv1.AppliedPolicy, defaultImage string) field.ErrorList {
+	if p == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	policies := strings.Join(p.Policies, ", ")
+	spec := field.NewPath("spec")
+
+	for _, label := range p.RequiredLabels {
+		if _, ok := hw.Labels[label]; !ok {
+			errs = append(errs, field.Required(field.NewPath("metadata", "labels").Key(label),
+				fmt.Sprintf("required by HelloWorldPolicy %s", policies)))
+		}
+	}
+
+	img := Image(hw, p, defaultImage)
+	ref, err := image.Parse(img)
+	if err != nil {
+		errs = append(errs, field.Invalid(spec.Child("image"), img, err.Error()))
+	} else if !ImageAllowed(p, ref) {
+		errs = append(errs, field.Forbidden(spec.Child("image"),
+			fmt.Sprintf("image %s is not allowed by HelloWorldPolicy %s", ref, policies)))
+	}
+
+	if p.MaxReplicas != nil && ptr.Deref(hw.Spec.Replicas, 1) > *p.MaxReplicas {
+		errs = append(errs, field.Invalid(spec.Child("replicas"), ptr.Deref(hw.Spec.Replicas, 1),
+			fmt.Sprintf("must be at most %d, the limit of HelloWorldPolicy %s", *p.MaxReplicas, policies)))
+	}
+
+	exposure := Exposure(hw, p)
+	if !ExposureAllowed(p, exposure) {
+		errs = append(errs, field.NotSupported(spec.Child("exposure"), exposure, exposureStrings(p.AllowedExposures)))
+	}
+
+	if p.MaxMessageSize != nil {
+		detail := fmt.Sprintf("must be at most %d bytes, the limit of HelloWorldPolicy %s", *p.MaxMessageSize, policies)
+		if size := len(hw.Spec.Message); size > int(*p.MaxMessageSize) {
+			errs = append(errs, field.Invalid(spec.Child("message"), fmt.Sprintf("%d bytes", size), detail))
+		}
+		for i, entry := range hw.Spec.Schedule {
+			if size := len(entry.Message); size > int(*p.MaxMessageSize) {
+				errs = append(errs, field.Invalid(spec.Child("schedule").Index(i).Child("message"),
+					fmt.Sprintf("%d bytes", size), detail))
+			}
+		}
+	}
+
+	return errs
+}
+
+func exposureStrings(exposures []helloworldv1.ExposureType) []string {
+	result := []string{}
+	for _, exposure := range exposures {
+		result = append(result, string(exposure))
+	}
+	return result
+}
+
+// Image returns the image hw is served with: its own, the default of the
+// policy, or defaultImage.
+func Image(hw *helloworldv1.HelloWorld, p *helloworldv1.AppliedPolicy, defaultImage string) string {
+	switch {
+	case hw.Spec.Image != "":
+		return hw.Spec.Image
+	case p != nil && p.DefaultImage != "":
+		return p.DefaultImage
+	default:
+		return defaultImage
+	}
+}
+
+// Exposure returns the exposure hw is served with: its own, the default of
+// the policy, or Route.
+func Exposure(hw *helloworldv1.HelloWorld, p *helloworldv1.AppliedPolicy) helloworldv1.ExposureType {
+	switch {
+	case hw.Spec.Exposure != "":
+		return hw.Spec.Exposure
+	case p != nil && p.DefaultExposure != "":
+		return p.DefaultExposure
+	default:
+		return helloworldv1.RouteExposureType
+	}
+}
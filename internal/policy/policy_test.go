@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+func helloWorldPolicy(name string, spec helloworldv1.HelloWorldPolicySpec) helloworldv1.HelloWorldPolicy {
+	return helloworldv1.HelloWorldPolicy{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+}
+
+var _ = Describe("Effective", func() {
+	ctx := context.Background()
+
+	var objects []client.Object
+
+	BeforeEach(func() {
+		objects = []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Labels: map[string]string{"tier": "tenant"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "system"}},
+		}
+	})
+
+	effective := func(namespace string) (*helloworldv1.AppliedPolicy, error) {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(helloworldv1.AddToScheme(scheme)).To(Succeed())
+		cli := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		return Effective(ctx, cli, namespace)
+	}
+
+	It("returns nil when no policy applies", func() {
+		Expect(effective("tenant")).To(BeNil())
+	})
+
+	It("combines the policies selecting the namespace", func() {
+		all := helloWorldPolicy("all", helloworldv1.HelloWorldPolicySpec{MaxReplicas: ptr.To(int32(5))})
+		tenants := helloWorldPolicy("tenants", helloworldv1.HelloWorldPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "tenant"}},
+			MaxReplicas:       ptr.To(int32(2)),
+		})
+		objects = append(objects, &all, &tenants)
+
+		Expect(effective("tenant")).To(Equal(&helloworldv1.AppliedPolicy{
+			Policies:    []string{"all", "tenants"},
+			MaxReplicas: ptr.To(int32(2)),
+		}))
+		Expect(effective("system")).To(Equal(&helloworldv1.AppliedPolicy{
+			Policies:    []string{"all"},
+			MaxReplicas: ptr.To(int32(5)),
+		}))
+	})
+
+	It("fails when the namespace does not exist", func() {
+		tenants := helloWorldPolicy("tenants", helloworldv1.HelloWorldPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "tenant"}},
+		})
+		objects = append(objects, &tenants)
+
+		_, err := effective("missing")
+		Expect(err).To(MatchError(ContainSubstring("failed to get namespace missing")))
+	})
+})
+
+var _ = Describe("Combine", func() {
+	It("combines limits to the strictest and takes the first defaults", func() {
+		Expect(Combine([]helloworldv1.HelloWorldPolicy{
+			helloWorldPolicy("b", helloworldv1.HelloWorldPolicySpec{
+				AllowedImages:    []string{"quay.io", "docker.io/library"},
+				DefaultImage:     "quay.io/b/nginx",
+				MaxReplicas:      ptr.To(int32(3)),
+				RequiredLabels:   []string{"team", "app"},
+				AllowedExposures: []helloworldv1.ExposureType{helloworldv1.ServiceExposureType},
+				DefaultExposure:  helloworldv1.ServiceExposureType,
+			}),
+			helloWorldPolicy("a", helloworldv1.HelloWorldPolicySpec{
+				AllowedImages:    []string{"quay.io/org", "docker.io"},
+				DefaultImage:     "quay.io/org/nginx",
+				MaxReplicas:      ptr.To(int32(5)),
+				RequiredLabels:   []string{"team"},
+				AllowedExposures: []helloworldv1.ExposureType{helloworldv1.RouteExposureType, helloworldv1.ServiceExposureType},
+				MaxMessageSize:   ptr.To(int32(1024)),
+			}),
+		})).To(Equal(&helloworldv1.AppliedPolicy{
+			Policies:         []string{"a", "b"},
+			AllowedImages:    []string{"quay.io/org", "docker.io/library"},
+			DefaultImage:     "quay.io/org/nginx",
+			MaxReplicas:      ptr.To(int32(3)),
+			RequiredLabels:   []string{"app", "team"},
+			AllowedExposures: []helloworldv1.ExposureType{helloworldv1.ServiceExposureType},
+			DefaultExposure:  helloworldv1.ServiceExposureType,
+			MaxMessageSize:   ptr.To(int32(1024)),
+		}))
+	})
+
+	It("allows nothing when the allowlists do not overlap", func() {
+		applied := Combine([]helloworldv1.HelloWorldPolicy{
+			helloWorldPolicy("a", helloworldv1.HelloWorldPolicySpec{AllowedImages: []string{"quay.io"}}),
+			helloWorldPolicy("b", helloworldv1.HelloWorldPolicySpec{AllowedImages: []string{"docker.io"}}),
+		})
+		Expect(applied.AllowedImages).To(BeEmpty())
+		Expect(applied.AllowedImages).NotTo(BeNil())
+
+		errs := Validate(&helloworldv1.HelloWorld{Spec: helloworldv1.HelloWorldSpec{Image: "quay.io/org/nginx"}}, applied, "")
+		Expect(errs.ToAggregate()).To(MatchError(ContainSubstring("is not allowed by HelloWorldPolicy a, b")))
+	})
+})
+
+var _ = Describe("Validate", func() {
+	applied := &helloworldv1.AppliedPolicy{
+		Policies:         []string{"tenants"},
+		AllowedImages:    []string{"quay.io/org"},
+		DefaultImage:     "quay.io/org/nginx",
+		MaxReplicas:      ptr.To(int32(2)),
+		RequiredLabels:   []string{"team"},
+		AllowedExposures: []helloworldv1.ExposureType{helloworldv1.ServiceExposureType},
+		MaxMessageSize:   ptr.To(int32(16)),
+	}
+
+	It("accepts HelloWorlds within the policy and its defaults", func() {
+		hw := &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}},
+			Spec: helloworldv1.HelloWorldSpec{
+				Message:  "Hello",
+				Exposure: helloworldv1.ServiceExposureType,
+			},
+		}
+		Expect(Validate(hw, applied, "docker.io/library/nginx")).To(BeEmpty())
+		Expect(Validate(hw, nil, "docker.io/library/nginx")).To(BeEmpty())
+	})
+
+	It("reports every violation", func() {
+		hw := &helloworldv1.HelloWorld{
+			Spec: helloworldv1.HelloWorldSpec{
+				Message:  strings.Repeat("x", 17),
+				Image:    "docker.io/library/nginx",
+				Replicas: ptr.To(int32(3)),
+				Schedule: []helloworldv1.ScheduleEntry{{Message: "Hi"}, {Message: strings.Repeat("y", 20)}},
+			},
+		}
+
+		errs := Validate(hw, applied, "")
+		Expect(errs).To(HaveLen(6))
+		Expect(errs.ToAggregate().Errors()).To(ConsistOf(
+			MatchError(`metadata.labels[team]: Required value: required by HelloWorldPolicy tenants`),
+			MatchError(`spec.image: Forbidden: image docker.io/library/nginx:latest is not allowed by HelloWorldPolicy tenants`),
+			MatchError(`spec.replicas: Invalid value: 3: must be at most 2, the limit of HelloWorldPolicy tenants`),
+			MatchError(`spec.exposure: Unsupported value: "Route": supported values: "Service"`),
+			MatchError(`spec.message: Invalid value: "17 bytes": must be at most 16 bytes, the limit of HelloWorldPolicy tenants`),
+			MatchError(`spec.schedule[1].message: Invalid value: "20 bytes": must be at most 16 bytes, the limit of HelloWorldPolicy tenants`),
+		))
+	})
+})
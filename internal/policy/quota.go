@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+// CombineQuotas returns the strictest of the quotas, or nil if none limits
+// anything.
+func CombineQuotas(quotas ...*helloworldv1.NamespaceQuota) *helloworldv1.NamespaceQuota {
+	combined := helloworldv1.NamespaceQuota{}
+	for _, quota := range quotas {
+		if quota == nil {
+			continue
+		}
+		combined.MaxHelloWorlds = minimum(combined.MaxHelloWorlds, quota.MaxHelloWorlds)
+		combined.MaxReplicas = minimum(combined.MaxReplicas, quota.MaxReplicas)
+	}
+	if combined.MaxHelloWorlds == nil && combined.MaxReplicas == nil {
+		return nil
+	}
+
+	return &combined
+}
+
+// Quota returns the quota of the namespace of a HelloWorld the policy
+// applies to: the strictest of the quota of the policy and base.
+func Quota(p *helloworldv1.AppliedPolicy, base *helloworldv1.NamespaceQuota) *helloworldv1.NamespaceQuota {
+	if p == nil {
+		return CombineQuotas(base)
+	}
+	return CombineQuotas(base, p.Quota)
+}
+
+// CheckQuota checks that hw is within the quota of its namespace. HelloWorlds
+// count against the quota in creation order, so hw is only over it if the
+// HelloWorlds created before it, and itself, exceed it. HelloWorlds not
+// created yet come last.
+func CheckQuota(
+	ctx context.Context, c client.Reader, hw *helloworldv1.HelloWorld, quota *helloworldv1.NamespaceQuota,
+) (field.ErrorList, error) {
+	if quota == nil {
+		return nil, nil
+	}
+	list := &helloworldv1.HelloWorldList{}
+	err := c.List(ctx, list, client.InNamespace(hw.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HelloWorlds: %w", err)
+	}
+
+	var before []helloworldv1.HelloWorld
+	for _, other := range list.Items {
+		if other.Name != hw.Name && other.DeletionTimestamp.IsZero() && CreatedBefore(&other, hw) {
+			before = append(before, other)
+		}
+	}
+
+	var errs field.ErrorList
+	if quota.MaxHelloWorlds != nil && int32(len(before)) >= *quota.MaxHelloWorlds {
+		errs = append(errs, field.Forbidden(field.NewPath("metadata", "namespace"),
+			fmt.Sprintf("namespace %s holds %d HelloWorlds created before this one, and its quota allows %d",
+				hw.Namespace, len(before), *quota.MaxHelloWorlds)))
+	}
+
+	if quota.MaxReplicas != nil {
+		replicas := ptr.Deref(hw.Spec.Replicas, 1)
+		for _, other := range before {
+			replicas += ptr.Deref(other.Spec.Replicas, 1)
+		}
+		if replicas > *quota.MaxReplicas {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "replicas"),
+				fmt.Sprintf("brings the HelloWorlds of namespace %s to %d replicas, and its quota allows %d",
+					hw.Namespace, replicas, *quota.MaxReplicas)))
+		}
+	}
+
+	return errs, nil
+}
+
+// CreatedBefore reports whether a was created before b, breaking ties by
+// name, which is the order HelloWorlds count against a quota in.
+func CreatedBefore(a, b *helloworldv1.HelloWorld) bool {
+	switch ta, tb := a.CreationTimestamp, b.CreationTimestamp; {
+	case ta.IsZero() != tb.IsZero():
+		return tb.IsZero()
+	case !ta.Equal(&tb):
+		return ta.Before(&tb)
+	default:
+		return strings.Compare(a.Name, b.Name) < 0
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("CombineQuotas", func() {
+	It("combines quotas to the strictest", func() {
+		Expect(CombineQuotas(nil, &helloworldv1.NamespaceQuota{})).To(BeNil())
+		Expect(CombineQuotas(
+			&helloworldv1.NamespaceQuota{MaxHelloWorlds: ptr.To(int32(5)), MaxReplicas: ptr.To(int32(10))},
+			nil,
+			&helloworldv1.NamespaceQuota{MaxHelloWorlds: ptr.To(int32(3))},
+		)).To(Equal(&helloworldv1.NamespaceQuota{MaxHelloWorlds: ptr.To(int32(3)), MaxReplicas: ptr.To(int32(10))}))
+	})
+})
+
+var _ = Describe("CheckQuota", func() {
+	ctx := context.Background()
+	created := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+
+	var cli client.Client
+
+	helloWorld := func(name string, age time.Duration, replicas int32) *helloworldv1.HelloWorld {
+		return &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "tenant",
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			},
+			Spec: helloworldv1.HelloWorldSpec{Replicas: ptr.To(replicas)},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(helloworldv1.AddToScheme(scheme)).To(Succeed())
+		other := helloWorld("other", 3*time.Hour, 5)
+		other.Namespace = "other"
+		cli = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+			helloWorld("oldest", 2*time.Hour, 2),
+			helloWorld("older", time.Hour, 2),
+			other,
+		).Build()
+	})
+
+	It("passes without a quota", func() {
+		Expect(CheckQuota(ctx, cli, helloWorld("new", 0, 10), nil)).To(BeEmpty())
+	})
+
+	It("counts the HelloWorlds created before", func() {
+		quota := &helloworldv1.NamespaceQuota{MaxHelloWorlds: ptr.To(int32(2))}
+
+		Expect(CheckQuota(ctx, cli, helloWorld("older", time.Hour, 2), quota)).To(BeEmpty())
+
+		errs, err := CheckQuota(ctx, cli, &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "tenant"},
+		}, quota)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs.ToAggregate()).To(MatchError(
+			"metadata.namespace: Forbidden: namespace tenant holds 2 HelloWorlds created before this one, and its quota allows 2"))
+	})
+
+	It("sums the replicas of the HelloWorlds created before", func() {
+		quota := &helloworldv1.NamespaceQuota{MaxReplicas: ptr.To(int32(5))}
+
+		Expect(CheckQuota(ctx, cli, helloWorld("oldest", 2*time.Hour, 5), quota)).To(BeEmpty())
+		Expect(CheckQuota(ctx, cli, helloWorld("new", 0, 1), quota)).To(BeEmpty())
+
+		errs, err := CheckQuota(ctx, cli, helloWorld("older", time.Hour, 4), quota)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs.ToAggregate()).To(MatchError(
+			"spec.replicas: Forbidden: brings the HelloWorlds of namespace tenant to 6 replicas, and its quota allows 5"))
+	})
+
+	It("orders HelloWorlds created at the same time by name", func() {
+		quota := &helloworldv1.NamespaceQuota{MaxHelloWorlds: ptr.To(int32(2))}
+
+		Expect(CheckQuota(ctx, cli, helloWorld("a", time.Hour, 1), quota)).To(BeEmpty())
+		Expect(CheckQuota(ctx, cli, helloWorld("z", time.Hour, 1), quota)).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package probe fetches the pages served by nginx, so the controller can
+// verify that the content it rolled out is what is actually served.
+package probe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a probe when HTTPProber.Timeout is not set
+	DefaultTimeout = 5 * time.Second
+	// DefaultMaxBodySize bounds the page read when HTTPProber.MaxBodySize is not set
+	DefaultMaxBodySize = 1 << 20
+)
+
+// Prober fetches the page served at a URL.
+type Prober interface {
+	Probe(ctx context.Context, url string) ([]byte, error)
+}
+
+// HTTPProber fetches pages with HTTP GET requests.
+type HTTPProber struct {
+	// Client is the HTTP client used to fetch pages, http.DefaultClient if nil
+	Client *http.Client
+	// Timeout bounds each probe, DefaultTimeout if zero
+	Timeout time.Duration
+	// MaxBodySize is the largest page read, DefaultMaxBodySize if zero.
+	// Larger pages fail the probe.
+	MaxBodySize int64
+}
+
+// Probe implements Prober.
+func (p *HTTPProber) Probe(ctx context.Context, url string) ([]byte, error) {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+
+	limit := p.MaxBodySize
+	if limit == 0 {
+		limit = DefaultMaxBodySize
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("GET %s returned more than %d bytes", url, limit)
+	}
+
+	return body, nil
+}
+
+func (p *HTTPProber) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+	return http.DefaultClient
+}
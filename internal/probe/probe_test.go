@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendatahub-io/sample-component/internal/probe/probetest"
+)
+
+var _ = Describe("HTTP prober", func() {
+	ctx := context.Background()
+
+	var server *probetest.Server
+	var prober *HTTPProber
+
+	BeforeEach(func() {
+		server = probetest.New()
+		prober = &HTTPProber{Client: server.Client()}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the served page", func() {
+		server.Serve("<h1>Hello</h1>")
+
+		body, err := prober.Probe(ctx, "http://hello-nginx.default.svc:8080/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal("<h1>Hello</h1>"))
+		Expect(server.Requests()).To(ConsistOf("hello-nginx.default.svc:8080/"))
+	})
+
+	It("fails on error statuses", func() {
+		server.Fail(http.StatusBadGateway)
+
+		_, err := prober.Probe(ctx, server.URL())
+		Expect(err).To(MatchError(ContainSubstring("502 Bad Gateway")))
+	})
+
+	It("fails on pages larger than the limit", func() {
+		server.Serve(strings.Repeat("x", 11))
+		prober.MaxBodySize = 10
+
+		_, err := prober.Probe(ctx, server.URL())
+		Expect(err).To(MatchError(ContainSubstring("more than 10 bytes")))
+
+		prober.MaxBodySize = 11
+		Expect(prober.Probe(ctx, server.URL())).To(HaveLen(11))
+	})
+
+	It("fails when the page is not served in time", func() {
+		server.Serve("<h1>Hello</h1>")
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := prober.Probe(canceled, server.URL())
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
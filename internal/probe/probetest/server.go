@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package probetest provides an in-process stand-in for nginx serving a page,
+// for testing code that probes the content HelloWorlds serve.
+package probetest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Server serves a single page on every path, with a configurable status.
+type Server struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	page     string
+	status   int
+	requests []string
+}
+
+// New starts a server serving an empty page. Callers must Close it.
+func New() *Server {
+	s := &Server{status: http.StatusOK}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL of the server.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Client returns an HTTP client that sends every request to the server,
+// whatever its host, so in-cluster Service URLs reach it.
+func (s *Server) Client() *http.Client {
+	addr := s.server.Listener.Addr().String()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}
+	return &http.Client{Transport: transport}
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Serve makes the server answer every request with page.
+func (s *Server) Serve(page string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.page = page
+	s.status = http.StatusOK
+}
+
+// Fail makes the server answer every request with status.
+func (s *Server) Fail(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// Requests returns the host and path of every request served so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req.Host+req.URL.Path)
+	page, status := s.page, s.status
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	if status == http.StatusOK {
+		_, _ = w.Write([]byte(page))
+	}
+}
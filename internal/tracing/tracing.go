@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing configures OpenTelemetry tracing of the controller from the
+// standard OTEL_* environment variables, and starts the spans reconciles are
+// traced with.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the tracer spans are started with
+	TracerName = "github.com/opendatahub-io/sample-component"
+	// ServiceName is the service.name of exported spans, unless overridden
+	// with OTEL_SERVICE_NAME or OTEL_RESOURCE_ATTRIBUTES
+	ServiceName = "helloworld-controller"
+)
+
+// Attributes set on reconcile spans.
+const (
+	// NameKey is the name of the reconciled custom resource
+	NameKey = attribute.Key("helloworld.name")
+	// NamespaceKey is the namespace of the reconciled custom resource
+	NamespaceKey = attribute.Key("helloworld.namespace")
+	// ChildKindKey is the kind of the child a span reconciles
+	ChildKindKey = attribute.Key("helloworld.child.kind")
+	// ResultKey is the outcome of the traced call
+	ResultKey = attribute.Key("helloworld.result")
+)
+
+// ResultError is the result of failed calls that do not report a more
+// specific one.
+const ResultError = "Error"
+
+// Start starts a span named name as a child of the span in ctx, with the
+// tracer provider of that span. Without a span in ctx nothing is traced.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(TracerName)
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the result of the call traced by span and the error it failed
+// with, if any, and ends the span. A failed call without a result gets
+// ResultError.
+func End(span trace.Span, result string, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if result == "" {
+			result = ResultError
+		}
+	}
+	span.SetAttributes(ResultKey.String(result))
+	span.End()
+}
+
+// Setup installs a global tracer provider exporting spans over OTLP/gRPC when
+// the environment asks for it, that is when OTEL_TRACES_EXPORTER is otlp, or
+// is unset and an OTLP endpoint is configured. The exporter, sampler, batch
+// processor and resource are configured from the standard OTEL_* variables.
+// It returns a function flushing and shutting down the provider, which does
+// nothing when tracing is disabled.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	enabled, err := enabled()
+	if err != nil || !enabled {
+		return noop, err
+	}
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("failed to detect the tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// enabled tells whether the environment enables exporting traces, failing
+// for exporters and protocols the controller does not support.
+func enabled() (bool, error) {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false, nil
+	}
+
+	switch exporter := os.Getenv("OTEL_TRACES_EXPORTER"); exporter {
+	case "none":
+		return false, nil
+	case "":
+		if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+			return false, nil
+		}
+	case "otlp":
+	default:
+		return false, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q, only otlp and none are supported", exporter)
+	}
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol != "" && protocol != "grpc" {
+		return false, fmt.Errorf("unsupported OTLP protocol %q, only grpc is supported", protocol)
+	}
+
+	return true, nil
+}
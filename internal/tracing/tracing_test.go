@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var _ = Describe("Spans", func() {
+	ctx := context.Background()
+
+	var exporter *tracetest.InMemoryExporter
+	var provider *sdktrace.TracerProvider
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	})
+
+	It("records children of the span in the context", func() {
+		ctx, parent := provider.Tracer(TracerName).Start(ctx, "parent")
+		_, span := Start(ctx, "child", NameKey.String("hello"))
+		End(span, "Applied", nil)
+		parent.End()
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name).To(Equal("child"))
+		Expect(spans[0].Parent.SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(spans[0].Attributes).To(ContainElements(NameKey.String("hello"), ResultKey.String("Applied")))
+		Expect(spans[0].Status.Code).To(Equal(codes.Unset))
+	})
+
+	It("records errors", func() {
+		ctx, parent := provider.Tracer(TracerName).Start(ctx, "parent")
+		_, span := Start(ctx, "child")
+		End(span, "", errors.New("boom"))
+		parent.End()
+
+		child := exporter.GetSpans()[0]
+		Expect(child.Status.Code).To(Equal(codes.Error))
+		Expect(child.Status.Description).To(Equal("boom"))
+		Expect(child.Attributes).To(ContainElement(ResultKey.String(ResultError)))
+		Expect(child.Events).To(ContainElement(HaveField("Name", "exception")))
+	})
+
+	It("traces nothing without a span in the context", func() {
+		_, span := Start(ctx, "child")
+		End(span, "Applied", nil)
+
+		Expect(span.SpanContext().IsValid()).To(BeFalse())
+		Expect(exporter.GetSpans()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Setup", func() {
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		for _, env := range []string{
+			"OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_ENDPOINT",
+			"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_PROTOCOL", "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL",
+		} {
+			GinkgoT().Setenv(env, "")
+		}
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		DeferCleanup(func() {
+			otel.SetTracerProvider(previous)
+		})
+	})
+
+	It("leaves tracing disabled without an OTLP endpoint", func() {
+		shutdown, err := Setup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shutdown(ctx)).To(Succeed())
+		Expect(otel.GetTracerProvider()).To(BeAssignableToTypeOf(noop.NewTracerProvider()))
+	})
+
+	It("exports to the configured OTLP endpoint", func() {
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4317")
+		GinkgoT().Setenv("OTEL_SERVICE_NAME", "custom")
+
+		shutdown, err := Setup(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(otel.GetTracerProvider()).To(BeAssignableToTypeOf(&sdktrace.TracerProvider{}))
+		Expect(shutdown(ctx)).To(Succeed())
+	})
+
+	DescribeTable("honours the environment",
+		func(env map[string]string, match types.GomegaMatcher) {
+			for k, v := range env {
+				GinkgoT().Setenv(k, v)
+			}
+
+			_, err := Setup(ctx)
+			Expect(err).To(match)
+			Expect(otel.GetTracerProvider()).To(BeAssignableToTypeOf(noop.NewTracerProvider()))
+		},
+		Entry("with the SDK disabled", map[string]string{
+			"OTEL_SDK_DISABLED":           "true",
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4317",
+		}, Succeed()),
+		Entry("with the none exporter", map[string]string{
+			"OTEL_TRACES_EXPORTER":        "none",
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4317",
+		}, Succeed()),
+		Entry("with an unsupported exporter", map[string]string{
+			"OTEL_TRACES_EXPORTER": "zipkin",
+		}, MatchError(ContainSubstring("unsupported OTEL_TRACES_EXPORTER"))),
+		Entry("with an unsupported protocol", map[string]string{
+			"OTEL_TRACES_EXPORTER":        "otlp",
+			"OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf",
+		}, MatchError(ContainSubstring("unsupported OTLP protocol"))),
+	)
+})
@@ -0,0 +1,43 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version reports the release of the component a binary was built
+// from.
+package version
+
+import (
+	"runtime/debug"
+)
+
+// RepoURL is the repository the component is released from
+const RepoURL = "https://github.com/opendatahub-io/sample-component"
+
+// Version is the release the binary was built from, set at build time with
+// -ldflags "-X github.com/opendatahub-io/sample-component/internal/version.Version=<version>"
+var Version string
+
+// Get returns the release the binary was built from: Version if it was set
+// at build time, otherwise the version of the main module recorded by the Go
+// toolchain, or "devel" for binaries built from a working tree.
+func Get() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "devel"
+}
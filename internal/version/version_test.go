@@ -0,0 +1,38 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Get", func() {
+	It("returns the version set at build time", func() {
+		DeferCleanup(func(version string) { Version = version }, Version)
+		Version = "v1.2.3"
+
+		Expect(Get()).To(Equal("v1.2.3"))
+	})
+
+	It("falls back to the version recorded by the Go toolchain", func() {
+		DeferCleanup(func(version string) { Version = version }, Version)
+		Version = ""
+
+		Expect(Get()).NotTo(BeEmpty())
+	})
+})
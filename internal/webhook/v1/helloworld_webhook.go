@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+	"github.com/opendatahub-io/sample-component/internal/policy"
+)
+
+// log is for logging in this package.
+var helloworldlog = logf.Log.WithName("helloworld-resource")
+
+// SetupHelloWorldWebhookWithManager registers the webhook for HelloWorld in the
+// manager. HelloWorlds without an image are checked as if they used
+// defaultImage, the image the controller serves them with, and every
+// namespace is limited by quota on top of the quotas of HelloWorldPolicies.
+func SetupHelloWorldWebhookWithManager(mgr ctrl.Manager, defaultImage string, quota *helloworldv1.NamespaceQuota) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&helloworldv1.HelloWorld{}).
+		WithValidator(&HelloWorldCustomValidator{Client: mgr.GetClient(), DefaultImage: defaultImage, Quota: quota}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-helloworld-opendatahub-io-v1-helloworld,mutating=false,failurePolicy=fail,sideEffects=None,groups=helloworld.opendatahub.io,resources=helloworlds,verbs=create;update,versions=v1,name=vhelloworld-v1.kb.io,admissionReviewVersions=v1
+
+// HelloWorldCustomValidator rejects HelloWorlds that break the
+// HelloWorldPolicies applying to their namespace, or would exceed its quota.
+type HelloWorldCustomValidator struct {
+	// Client reads HelloWorldPolicies, namespaces and HelloWorlds
+	Client client.Reader
+	// DefaultImage is the image of HelloWorlds that do not set one, when no
+	// policy defaults it
+	DefaultImage string
+	// Quota limits every namespace, on top of the quotas of HelloWorldPolicies
+	Quota *helloworldv1.NamespaceQuota
+}
+
+var _ webhook.CustomValidator = &HelloWorldCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type HelloWorld.
+func (v *HelloWorldCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	hw, ok := obj.(*helloworldv1.HelloWorld)
+	if !ok {
+		return nil, fmt.Errorf("expected a HelloWorld object but got %T", obj)
+	}
+	helloworldlog.V(1).Info("Validation for HelloWorld upon creation", "name", hw.GetName(), "namespace", hw.GetNamespace())
+
+	return nil, v.validate(ctx, hw, nil, true)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type HelloWorld.
+// Updates are only rejected for what they break: a HelloWorld created
+// before a policy may keep breaking it, so that its labels and annotations
+// can still be changed, by the controller among others. The quota is only
+// checked by updates asking for more replicas, so HelloWorlds over it can
+// still be scaled down.
+func (v *HelloWorldCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*helloworldv1.HelloWorld)
+	if !ok {
+		return nil, fmt.Errorf("expected a HelloWorld object for the oldObj but got %T", oldObj)
+	}
+	hw, ok := newObj.(*helloworldv1.HelloWorld)
+	if !ok {
+		return nil, fmt.Errorf("expected a HelloWorld object for the newObj but got %T", newObj)
+	}
+	helloworldlog.V(1).Info("Validation for HelloWorld upon update", "name", hw.GetName(), "namespace", hw.GetNamespace())
+
+	scaledUp := ptr.Deref(hw.Spec.Replicas, 1) > ptr.Deref(old.Spec.Replicas, 1)
+	return nil, v.validate(ctx, hw, old, scaledUp)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type HelloWorld.
+// HelloWorlds may always be deleted.
+func (v *HelloWorldCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks hw against the HelloWorldPolicies applying to its
+// namespace, leaving out the errors old already had when it updates old,
+// and against its quota if checkQuota is set.
+func (v *HelloWorldCustomValidator) validate(
+	ctx context.Context, hw, old *helloworldv1.HelloWorld, checkQuota bool,
+) error {
+	applied, err := policy.Effective(ctx, v.Client, hw.Namespace)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	errs := policy.Validate(hw, applied, v.DefaultImage)
+	if old != nil {
+		broken := policy.Validate(old, applied, v.DefaultImage)
+		errs = slices.DeleteFunc(errs, func(err *field.Error) bool {
+			return slices.ContainsFunc(broken, func(b *field.Error) bool { return b.Error() == err.Error() })
+		})
+	}
+	if checkQuota {
+		overQuota, err := policy.CheckQuota(ctx, v.Client, hw, policy.Quota(applied, v.Quota))
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		errs = append(errs, overQuota...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(helloworldv1.GroupVersion.WithKind("HelloWorld").GroupKind(), hw.Name, errs)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	helloworldv1 "github.com/opendatahub-io/sample-component/api/v1"
+)
+
+var _ = Describe("HelloWorld Webhook", func() {
+	ctx := context.Background()
+
+	var cli client.Client
+	var validator *HelloWorldCustomValidator
+	var hw *helloworldv1.HelloWorld
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(helloworldv1.AddToScheme(scheme)).To(Succeed())
+		cli = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Labels: map[string]string{"tier": "tenant"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "system"}},
+			&helloworldv1.HelloWorldPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+				Spec: helloworldv1.HelloWorldPolicySpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "tenant"}},
+					AllowedImages:     []string{"quay.io/org"},
+					MaxReplicas:       ptr.To(int32(2)),
+				},
+			},
+		).Build()
+		validator = &HelloWorldCustomValidator{Client: cli, DefaultImage: "docker.io/library/nginx"}
+
+		hw = &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "tenant"},
+			Spec: helloworldv1.HelloWorldSpec{
+				Image:    "quay.io/org/nginx",
+				Replicas: ptr.To(int32(2)),
+			},
+		}
+	})
+
+	It("admits HelloWorlds complying with the policies of their namespace", func() {
+		Expect(validator.ValidateCreate(ctx, hw)).To(BeEmpty())
+		Expect(validator.ValidateUpdate(ctx, hw, hw)).To(BeEmpty())
+	})
+
+	It("rejects HelloWorlds breaking a policy of their namespace", func() {
+		hw.Spec.Replicas = ptr.To(int32(3))
+		_, err := validator.ValidateCreate(ctx, hw)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("must be at most 2, the limit of HelloWorldPolicy tenants")))
+
+		compliant := hw.DeepCopy()
+		compliant.Spec.Replicas = ptr.To(int32(2))
+		_, err = validator.ValidateUpdate(ctx, compliant, hw)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("admits updates to HelloWorlds created before a policy that leave alone what breaks it", func() {
+		hw.Spec.Replicas = ptr.To(int32(3))
+
+		By("admitting an annotation patched by the controller")
+		annotated := hw.DeepCopy()
+		annotated.Annotations = map[string]string{"helloworld.opendatahub.io/inventory": "{}"}
+		Expect(validator.ValidateUpdate(ctx, hw, annotated)).To(BeEmpty())
+
+		By("rejecting an update that breaks the policy further")
+		scaledUp := hw.DeepCopy()
+		scaledUp.Spec.Replicas = ptr.To(int32(4))
+		_, err := validator.ValidateUpdate(ctx, hw, scaledUp)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+		By("rejecting an update that breaks another rule of the policy")
+		reimaged := hw.DeepCopy()
+		reimaged.Spec.Image = "docker.io/library/nginx"
+		_, err = validator.ValidateUpdate(ctx, hw, reimaged)
+		Expect(err).To(MatchError(ContainSubstring("is not allowed by HelloWorldPolicy tenants")))
+		Expect(err).NotTo(MatchError(ContainSubstring("spec.replicas")))
+	})
+
+	It("checks HelloWorlds without an image against the default image", func() {
+		hw.Spec.Image = ""
+		_, err := validator.ValidateCreate(ctx, hw)
+		Expect(err).To(MatchError(ContainSubstring("image docker.io/library/nginx:latest is not allowed")))
+	})
+
+	It("ignores policies not selecting the namespace", func() {
+		hw.Namespace = "system"
+		hw.Spec.Image = ""
+		hw.Spec.Replicas = ptr.To(int32(10))
+		Expect(validator.ValidateCreate(ctx, hw)).To(BeEmpty())
+	})
+
+	It("rejects HelloWorlds over the quota of their namespace", func() {
+		validator.Quota = &helloworldv1.NamespaceQuota{MaxReplicas: ptr.To(int32(3))}
+		Expect(cli.Create(ctx, &helloworldv1.HelloWorld{
+			ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "tenant"},
+			Spec:       helloworldv1.HelloWorldSpec{Image: "quay.io/org/nginx", Replicas: ptr.To(int32(2))},
+		})).To(Succeed())
+
+		_, err := validator.ValidateCreate(ctx, hw)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("brings the HelloWorlds of namespace tenant to 4 replicas")))
+
+		By("only checking the quota of updates asking for more replicas")
+		scaledDown := hw.DeepCopy()
+		scaledDown.Spec.Replicas = ptr.To(int32(1))
+		Expect(validator.ValidateUpdate(ctx, hw, hw)).To(BeEmpty())
+		Expect(validator.ValidateUpdate(ctx, hw, scaledDown)).To(BeEmpty())
+		_, err = validator.ValidateUpdate(ctx, scaledDown, hw)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("admits deletions", func() {
+		hw.Spec.Replicas = ptr.To(int32(3))
+		Expect(validator.ValidateDelete(ctx, hw)).To(BeEmpty())
+	})
+})